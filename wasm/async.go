@@ -0,0 +1,66 @@
+package wasm
+
+import (
+	"reflect"
+	"syscall/js"
+)
+
+// asyncFunc is a Go function converted to an async JS function.
+type asyncFunc struct {
+	fn reflect.Value
+}
+
+// Async marks the provided Go function as asynchronous. It is converted to a JS function that calls it in its own
+// goroutine and returns a Promise, which is fulfilled with its return values or rejected with its error. The function
+// can therefore block, such as by awaiting a Promise, without freezing JS.
+//
+// The arguments are decoded before the goroutine starts, and errors decoding them reject the Promise. A leading
+// context.Context parameter is cancelled when the AbortSignal passed by JS aborts, while the function runs too, unlike
+// with sync functions which only see a signal that aborted before the call.
+//
+// It panics if fn is not a function.
+func Async(fn interface{}) Wrapper {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic("cannot make " + reflect.TypeOf(fn).String() + " asynchronous as it is not a function")
+	}
+	return asyncFunc{v}
+}
+
+// ExposeAsync exposes the provided Go function in JS as an asynchronous function, as described by Async.
+// It returns the error of Expose, if any.
+func ExposeAsync(property string, fn interface{}) error {
+	return Expose(property, Async(fn))
+}
+
+// ExposeAsync exposes the provided Go function in JS as an asynchronous function like the package-level ExposeAsync,
+// converting it with the options of the Encoder.
+func (enc *Encoder) ExposeAsync(property string, fn interface{}) error {
+	return enc.Expose(property, Async(fn))
+}
+
+// JSValue implements the Wrapper interface. An Encoder converts the function with its own options instead.
+func (a asyncFunc) JSValue() js.Value {
+	return toAsyncJSFunc(a.fn, defaultEncoder.opts).JSValue()
+}
+
+// toAsyncJSFunc takes a reflect.Value of a Go function and converts it to a JS function that returns a Promise, as
+// described by Async.
+func toAsyncJSFunc(x reflect.Value, opts EncoderOptions) Func {
+	funcType := x.Type()
+	return throwingFunc(func(this js.Value, args []js.Value) (js.Value, error) {
+		ctx, args, cancel := signalContext(funcType, args, true)
+		in, decodeErr := conformJSValueToType(x, ctx, this, args, NewDecoder(opts.Arguments))
+
+		promise := NewPromise(func() (result interface{}, err error) {
+			defer cancel()
+			if decodeErr != nil {
+				return nil, decodeErr
+			}
+
+			defer recoverPanic(&err)
+			return callFunc(x, in, opts)
+		})
+		return promise.JSValue(), nil
+	})
+}
@@ -0,0 +1,206 @@
+package wasm
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"syscall/js"
+)
+
+var (
+	bigIntType  = reflect.TypeOf(big.Int{})
+	jsErrorType = reflect.TypeOf(JSError{})
+)
+
+// InvalidBigIntError is an error where a JS BigInt cannot be unmarshalled into the provided Go type, either because the
+// type cannot hold integers or because the value overflows it.
+type InvalidBigIntError struct {
+	Value  string
+	GoType reflect.Type
+}
+
+// Error implements error.
+func (e InvalidBigIntError) Error() string {
+	return "invalid unmarshalling: cannot unmarshal BigInt " + e.Value + " into " + e.GoType.String()
+}
+
+// jsType returns the type of the provided js.Value.
+// It returns false if the value is a BigInt, which js.Value.Type panics on. Recovering from the panic is much cheaper
+// than asking JS for the type, but it should still only be done once per value.
+func jsType(x js.Value) (t js.Type, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return x.Type(), true
+}
+
+// isBigInt checks if the provided js.Value is a BigInt.
+func isBigInt(x js.Value) bool {
+	_, ok := jsType(x)
+	return !ok
+}
+
+// bigIntToJS converts the decimal representation of an integer to a JS BigInt.
+func bigIntToJS(s string) js.Value {
+	return globalConstructor("BigInt").Invoke(s)
+}
+
+// int64Encoder converts an int64 or an int to a JS number, or to a BigInt if the BigInts option is set.
+func int64Encoder(e *encodeState, v reflect.Value) js.Value {
+	if e.opts.BigInts {
+		return bigIntToJS(strconv.FormatInt(v.Int(), 10))
+	}
+	return js.ValueOf(v.Int())
+}
+
+// uint64Encoder converts a uint64 or a uint to a JS number, or to a BigInt if the BigInts option is set.
+func uint64Encoder(e *encodeState, v reflect.Value) js.Value {
+	if e.opts.BigInts {
+		return bigIntToJS(strconv.FormatUint(v.Uint(), 10))
+	}
+	return js.ValueOf(v.Uint())
+}
+
+// bigIntEncoder converts a big.Int or a *big.Int to a JS string, or to a BigInt if the BigInts option is set.
+func bigIntEncoder(e *encodeState, v reflect.Value) js.Value {
+	if e.opts.BigInts {
+		return forcedBigIntEncoder(e, v)
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return js.Undefined()
+	}
+	return js.ValueOf(bigIntPointer(v).String())
+}
+
+// forcedBigIntEncoder converts 64-bit integers, big.Int and pointers to them to a JS BigInt, as requested by the
+// bigint option of the wasm tag. Other values are converted as usual.
+func forcedBigIntEncoder(e *encodeState, v reflect.Value) js.Value {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return js.Undefined()
+		}
+		if v.Type().Elem() != bigIntType {
+			return forcedBigIntEncoder(e, v.Elem())
+		}
+	}
+
+	switch {
+	case v.Type() == bigIntType || v.Type() == reflect.PtrTo(bigIntType):
+		return bigIntToJS(bigIntPointer(v).String())
+	case v.Kind() == reflect.Int64 || v.Kind() == reflect.Int:
+		return bigIntToJS(strconv.FormatInt(v.Int(), 10))
+	case v.Kind() == reflect.Uint64 || v.Kind() == reflect.Uint:
+		return bigIntToJS(strconv.FormatUint(v.Uint(), 10))
+	default:
+		return typeEncoder(v.Type())(e, v)
+	}
+}
+
+// bigIntPointer returns a *big.Int holding the value of the provided big.Int or *big.Int.
+func bigIntPointer(v reflect.Value) *big.Int {
+	if v.Kind() == reflect.Ptr {
+		return v.Interface().(*big.Int)
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface().(*big.Int)
+	}
+
+	ptr := reflect.New(bigIntType)
+	ptr.Elem().Set(v)
+	return ptr.Interface().(*big.Int)
+}
+
+// inspectsJSType checks if the decoder of the provided type calls js.Value.Type, which panics on BigInts, so that
+// BigInts must be told apart before calling it. Telling them apart is costly, so it is only done when needed: pointers
+// leave it to the decoder of the pointed type, interface{} checks for BigInts itself, while js.Value, error and
+// implementations of Decoder accept any value.
+func inspectsJSType(t reflect.Type) bool {
+	switch {
+	case t.Kind() == reflect.Ptr, t == jsValueType, t == errorType, decodesBigInts(t):
+		return false
+	case t.Kind() == reflect.Interface && t.NumMethod() == 0:
+		_, ok := typeVariants(t)
+		return ok
+	}
+	return true
+}
+
+// decodesBigInts checks if the provided type implements Decoder and is trusted to handle BigInts itself.
+// Decoders declared in this package, apart from JSError, only accept specific JS types, so BigInts are rejected before
+// calling them.
+func decodesBigInts(t reflect.Type) bool {
+	if !reflect.PtrTo(t).Implements(decoderType) {
+		return false
+	}
+	return t.PkgPath() != decoderType.PkgPath() || t == jsErrorType
+}
+
+// newBigIntCheckDecoder returns a decoderFunc that decodes JS BigInts with bigIntDec and any other value with dec.
+func newBigIntCheckDecoder(dec, bigIntDec decoderFunc) decoderFunc {
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if isBigInt(x) {
+			return bigIntDec(d, x, v)
+		}
+		return dec(d, x, v)
+	}
+}
+
+// newBigIntDecoder returns the decoderFunc used when a JS BigInt is decoded into the provided type.
+// dec is the decoder of the type, used for pointers and implementations of Decoder that handle BigInts.
+func newBigIntDecoder(t reflect.Type, dec decoderFunc) decoderFunc {
+	if t.Kind() == reflect.Ptr || decodesBigInts(t) {
+		return dec
+	}
+	return decodeBigInt
+}
+
+// decodeBigInt decodes a JS BigInt into the provided reflect.Value.
+// Integers must not overflow the Go type, while floats are rounded to the nearest value.
+func decodeBigInt(d *decodeState, x js.Value, v reflect.Value) error {
+	s := bigIntString(x)
+
+	if v.Type() == bigIntType {
+		v.Addr().Interface().(*big.Int).SetString(s, 10)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return InvalidBigIntError{s, v.Type()}
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return InvalidBigIntError{s, v.Type()}
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, _ := strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(n)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return InvalidBigIntError{s, v.Type()}
+		}
+		v.Set(reflect.ValueOf(createBigInt(x)))
+	default:
+		return InvalidBigIntError{s, v.Type()}
+	}
+	return nil
+}
+
+// createBigInt creates a *big.Int representing the provided JS BigInt.
+func createBigInt(x js.Value) *big.Int {
+	n, _ := new(big.Int).SetString(bigIntString(x), 10)
+	return n
+}
+
+// bigIntString returns the decimal representation of the provided JS BigInt.
+// Methods cannot be called on primitives through js.Value.Call, so the global String function is used instead.
+func bigIntString(x js.Value) string {
+	return globalConstructor("String").Invoke(x).String()
+}
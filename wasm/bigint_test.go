@@ -0,0 +1,118 @@
+package wasm
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+func TestDecodeBigInt(t *testing.T) {
+	type quoted struct {
+		N  int64  `wasm:"n,string"`
+		P  *int64 `wasm:"p,string"`
+		OK bool   `wasm:"ok,string"`
+	}
+	five, seven := int64(5), int64(7)
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    interface{}
+		wantErr bool
+	}{
+		{"int64", "2n ** 62n", int64(1) << 62, false},
+		{"uint", "2n ** 63n", uint(1) << 63, false},
+		{"float", "3n", 3.0, false},
+		{"pointer", "5n", &five, false},
+		{"big.Int", "2n ** 70n", *new(big.Int).Lsh(big.NewInt(1), 70), false},
+		{"interface", "[1n]", []interface{}{big.NewInt(1)}, false},
+		{"overflow", "2n ** 8n", int8(0), true},
+		{"string", "1n", "", true},
+		{"struct", "1n", quoted{}, true},
+		{"quoted", `({n: 1n, p: "7", ok: "true"})`, quoted{N: 1, P: &seven, OK: true}, false},
+		{"quoted number", `({n: 2, p: 7n})`, quoted{N: 2, P: &seven}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := reflect.New(reflect.TypeOf(tt.want))
+			err := FromJSValue(evalJS(tt.expr), out.Interface())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("FromJSValue(%s) = %#v, want an error", tt.expr, out.Elem().Interface())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromJSValue(%s) returned error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(out.Elem().Interface(), tt.want) {
+				t.Errorf("FromJSValue(%s) = %#v, want %#v", tt.expr, out.Elem().Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBigIntIntoPackageTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		into interface{}
+	}{
+		{"Promise", new(Promise)},
+		{"Object", new(Object)},
+		{"Symbol", new(Symbol)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromJSValue(evalJS("42n"), tt.into)
+			var bigIntErr InvalidBigIntError
+			if !errors.As(err, &bigIntErr) {
+				t.Errorf("FromJSValue(42n) returned %v, want an InvalidBigIntError", err)
+			}
+		})
+	}
+
+	var jsErr JSError
+	if err := FromJSValue(evalJS("42n"), &jsErr); err != nil {
+		t.Fatalf("FromJSValue(42n) into JSError returned error: %v", err)
+	}
+	if jsErr.Message != "42" {
+		t.Errorf("JSError.Message = %q, want %q", jsErr.Message, "42")
+	}
+}
+
+func TestEncodeBigInts(t *testing.T) {
+	enc := NewEncoder(EncoderOptions{BigInts: true})
+	typeOf := js.Global().Get("Function").New("x", "return typeof x")
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{"int", 1 << 60, "bigint"},
+		{"uint", uint(1) << 60, "bigint"},
+		{"int64", int64(1), "bigint"},
+		{"int32", int32(1), "number"},
+		{"field", struct{ N int }{1}, "bigint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := enc.ToJSValue(tt.in)
+			if tt.name == "field" {
+				x = x.Get("N")
+			}
+			if got := typeOf.Invoke(x).String(); got != tt.want {
+				t.Errorf("ToJSValue(%#v) is a %s, want a %s", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if got := typeOf.Invoke(ToJSValue(1)).String(); got != "number" {
+		t.Errorf("ToJSValue(1) without the BigInts option is a %s, want a number", got)
+	}
+}
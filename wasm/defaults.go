@@ -0,0 +1,63 @@
+package wasm
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	defaultsMu sync.RWMutex
+
+	// typeDefaults holds the default values registered for parameters of specific types.
+	typeDefaults = map[reflect.Type]reflect.Value{}
+)
+
+// RegisterDefault registers the provided value as the default of the parameters of its type, such as SearchOptions{}
+// for SearchOptions, so that JS can omit them when calling a Go function. Pointer parameters are optional without a
+// default and are nil when omitted.
+//
+// Only trailing parameters are optional: a parameter can be omitted only if every parameter after it can be omitted
+// too. Passing undefined is the same as omitting the parameter. The default is copied into the parameter, so maps,
+// slices and pointers it holds are shared between calls and must not be modified.
+//
+// It panics if x is nil.
+// It should be called before any Go function taking parameters of the type is called, such as in an init function.
+func RegisterDefault(x interface{}) {
+	if x == nil {
+		panic("cannot register nil as a default value")
+	}
+	v := reflect.ValueOf(x)
+
+	defaultsMu.Lock()
+	typeDefaults[v.Type()] = v
+	defaultsMu.Unlock()
+}
+
+// paramDefault returns the value of a parameter of the provided type that is omitted by JS, or false if the parameter
+// cannot be omitted.
+func paramDefault(t reflect.Type) (reflect.Value, bool) {
+	defaultsMu.RLock()
+	v, ok := typeDefaults[t]
+	defaultsMu.RUnlock()
+
+	if ok {
+		return v, true
+	}
+	if t.Kind() == reflect.Ptr {
+		return reflect.Zero(t), true
+	}
+	return reflect.Value{}, false
+}
+
+// requiredParams returns how many of the fixed parameters of the provided function type, starting with the one at
+// index first, JS must pass: every parameter up to the last one that cannot be omitted.
+func requiredParams(funcType reflect.Type, first, fixed int) int {
+	required := fixed
+	for required > 0 {
+		if _, ok := paramDefault(funcType.In(first + required - 1)); !ok {
+			break
+		}
+		required--
+	}
+	return required
+}
@@ -0,0 +1,93 @@
+package wasm
+
+import (
+	"context"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+type defaultsOptions struct {
+	Limit int
+}
+
+func init() {
+	RegisterDefault(defaultsOptions{Limit: 10})
+}
+
+func TestParamDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want interface{}
+		ok   bool
+	}{
+		{"registered", reflect.TypeOf(defaultsOptions{}), defaultsOptions{Limit: 10}, true},
+		{"pointer", reflect.TypeOf(&defaultsOptions{}), (*defaultsOptions)(nil), true},
+		{"unregistered", reflect.TypeOf(0), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := paramDefault(tt.typ)
+			if ok != tt.ok {
+				t.Fatalf("paramDefault(%v) ok = %v, want %v", tt.typ, ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(v.Interface(), tt.want) {
+				t.Errorf("paramDefault(%v) = %#v, want %#v", tt.typ, v.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    interface{}
+		first int
+		fixed int
+		want  int
+	}{
+		{"no parameters", func() {}, 0, 0, 0},
+		{"required", func(string, int) {}, 0, 2, 2},
+		{"trailing default", func(string, defaultsOptions) {}, 0, 2, 1},
+		{"trailing pointer and default", func(string, *int, defaultsOptions) {}, 0, 3, 1},
+		{"default before required", func(defaultsOptions, string) {}, 0, 2, 2},
+		{"context", func(context.Context, string, defaultsOptions) {}, 1, 2, 1},
+		{"this", func(js.Value, defaultsOptions) {}, 0, 2, 1},
+		{"variadic", func(string, defaultsOptions, ...int) {}, 0, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiredParams(reflect.TypeOf(tt.fn), tt.first, tt.fixed); got != tt.want {
+				t.Errorf("requiredParams = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOmittedArguments(t *testing.T) {
+	limit := func(query string, opts defaultsOptions) int {
+		return opts.Limit
+	}
+	fn := ToJSValue(limit)
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want int
+	}{
+		{"omitted", []interface{}{"q"}, 10},
+		{"undefined", []interface{}{"q", js.Undefined()}, 10},
+		{"passed", []interface{}{"q", map[string]interface{}{"Limit": 3}}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fn.Invoke(tt.args...).Int(); got != tt.want {
+				t.Errorf("limit(%v) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
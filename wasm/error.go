@@ -1,13 +1,137 @@
 package wasm
 
-import "syscall/js"
+import (
+	"errors"
+	"syscall/js"
+)
+
+// ErrorNamer is implemented by Go errors that set the name of the JS Error created by NewError, such as
+// "NotFoundError".
+type ErrorNamer interface {
+	JSName() string
+}
+
+// ErrorPropsProvider is implemented by Go errors that set additional properties, such as a code, on the JS Error
+// created by NewError. The values are converted with ToJSValue.
+type ErrorPropsProvider interface {
+	JSProps() map[string]interface{}
+}
 
 // NewError returns a JS Error with the provided Go error's error message.
+//
+// The error wrapped by the Go error, as returned by errors.Unwrap, is converted to an Error as well and set as the
+// cause of the Error, and so on for the whole chain. The name and additional properties of the Error are taken from
+// the ErrorNamer and ErrorPropsProvider implementations of the Go error. A *JSError that holds a JS Error is converted
+// back to the original value, while other values are replaced by an Error with the same name and message.
 func NewError(goErr error) js.Value {
-	errConstructor, err := Global().Expect(js.TypeFunction, "Error")
-	if err != nil {
-		panic("Error constructor not found")
+	message := goErr.Error()
+	if jsErr, ok := goErr.(*JSError); ok {
+		// Functions only throw Errors, so other values, such as the reason of Promise.reject("x"), are turned into one.
+		if jsErr.Value.InstanceOf(globalConstructor("Error")) {
+			return jsErr.Value
+		}
+		message = jsErr.Message
+	}
+
+	options := globalConstructor("Object").New()
+	if cause := errors.Unwrap(goErr); cause != nil {
+		options.Set("cause", NewError(cause))
+	}
+	jsErr := globalConstructor("Error").New(message, options)
+
+	if p, ok := goErr.(ErrorPropsProvider); ok {
+		for k, v := range p.JSProps() {
+			jsErr.Set(k, ToJSValue(v))
+		}
 	}
+	if n, ok := goErr.(ErrorNamer); ok && n.JSName() != "" {
+		jsErr.Set("name", n.JSName())
+	}
+	return jsErr
+}
+
+// JSError is a JS Error, or any other value thrown or rejected by JS, as a Go error.
+// Use errors.As to tell errors apart by their name, such as TypeError or AbortError.
+type JSError struct {
+	// Name is the name property of the Error, such as "TypeError". It is empty for values other than objects.
+	Name string
+	// Message is the message property of the Error, or the string form of a value other than an object.
+	Message string
+	// Stack is the stack property of the Error, if the JS engine sets it.
+	Stack string
+	// Cause is the cause property of the Error as a *JSError, or nil if it has none.
+	Cause error
+	// Value is the original JS value, which holds any other property of the Error.
+	Value js.Value
+}
 
-	return errConstructor.New(goErr.Error())
+// Error implements error, formatting the error like Error.prototype.toString.
+func (e *JSError) Error() string {
+	switch {
+	case e.Name == "":
+		return e.Message
+	case e.Message == "":
+		return e.Name
+	default:
+		return e.Name + ": " + e.Message
+	}
+}
+
+// JSName implements ErrorNamer, so that the name of the error is kept when it is converted back to JS.
+func (e *JSError) JSName() string {
+	return e.Name
+}
+
+// Unwrap returns the cause of the error.
+func (e *JSError) Unwrap() error {
+	return e.Cause
+}
+
+// FromJSValue turns a JS value to a JSError. Any JS value can be decoded, as JS can throw any value.
+func (e *JSError) FromJSValue(value js.Value) error {
+	*e = *newJSError(value, nil)
+	return nil
+}
+
+// JSValue implements the Wrapper interface, returning the original JS value.
+// An Error is created with NewError if it has none, such as when it was created in Go.
+func (e *JSError) JSValue() js.Value {
+	return NewError(e)
+}
+
+// newJSError converts the provided JS value to a JSError, following the chain of causes.
+// Causes that were already visited are left out, so that an error that is its own cause does not loop forever.
+func newJSError(x js.Value, visited []js.Value) *JSError {
+	e := &JSError{Value: x}
+	// Any value can be thrown, including a BigInt, which jsType reports as not ok.
+	if t, ok := jsType(x); !ok || t != js.TypeObject && t != js.TypeFunction {
+		e.Message = globalConstructor("String").Invoke(x).String()
+		return e
+	}
+
+	e.Name = stringProperty(x, "name")
+	e.Message = stringProperty(x, "message")
+	e.Stack = stringProperty(x, "stack")
+
+	visited = append(visited, x)
+	cause := x.Get("cause")
+	if cause.IsUndefined() {
+		return e
+	}
+	for _, v := range visited {
+		if v.Equal(cause) {
+			return e
+		}
+	}
+	e.Cause = newJSError(cause, visited)
+	return e
+}
+
+// stringProperty returns the named property of the provided JS object if it is a string, or an empty string.
+func stringProperty(x js.Value, name string) string {
+	property := x.Get(name)
+	if t, ok := jsType(property); !ok || t != js.TypeString {
+		return ""
+	}
+	return property.String()
 }
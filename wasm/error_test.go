@@ -0,0 +1,90 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewJSError(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"Error", `new TypeError("boom")`, "TypeError: boom"},
+		{"string", `"boom"`, "boom"},
+		{"number", `42`, "42"},
+		{"BigInt", `42n`, "42"},
+		{"BigInt message", `Object.assign(new Error(), {message: 42n})`, "Error"},
+		{"cause", `new Error("outer", {cause: new Error("inner")})`, "Error: outer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if decodeErr := FromJSValue(evalJS(tt.expr), &err); decodeErr != nil {
+				t.Fatalf("FromJSValue returned error: %v", decodeErr)
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("FromJSValue decoded %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAwaitBigIntRejection(t *testing.T) {
+	var p Promise
+	if err := FromJSValue(evalJS(`Promise.reject(42n)`), &p); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+
+	var jsErr *JSError
+	if err := p.Await(nil); !errors.As(err, &jsErr) || jsErr.Message != "42" {
+		t.Errorf("Await returned %v, want a *JSError with message 42", err)
+	}
+}
+
+func TestNewErrorRoundTrip(t *testing.T) {
+	ExposeAsync("awaitReason", func(p Promise) error {
+		return p.Await(nil)
+	})
+	Expose("returnReason", func(err error) error {
+		return err
+	})
+	defer bridge.Delete("awaitReason")
+	defer bridge.Delete("returnReason")
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"async string", `bridge.awaitReason(Promise.reject("x"))`, "Error: x"},
+		{"async Error", `bridge.awaitReason(Promise.reject(new TypeError("x")))`, "TypeError: x"},
+		{"sync string", `bridge.returnReason("x")`, "Error: x"},
+		{"sync object", `bridge.returnReason({code: 1})`, "Error: "},
+		{"sync Error", `bridge.returnReason(new RangeError("x"))`, "RangeError: x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result describes the thrown or rejected value if it is an Error.
+			run := evalJS(`async (bridge) => {
+				try {
+					await ` + tt.expr + `;
+					return "no error";
+				} catch (e) {
+					return e instanceof Error ? e.name + ": " + e.message : "not an Error: " + String(e);
+				}
+			}`)
+
+			var got string
+			if err := mustJSValueToPromise(run.Invoke(bridge.value)).Await(&got); err != nil {
+				t.Fatalf("Await returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%s threw %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package wasm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field is an exported struct field as seen from JS.
+type field struct {
+	name  string
+	tag   bool
+	index []int
+	typ   reflect.Type
+
+	// Go field name, used for error messages.
+	goName string
+
+	omitEmpty bool
+	quoted    bool
+	required  bool
+	jsMap     bool
+	bigInt    bool
+}
+
+// typeFields returns the fields of the provided struct type that are converted to and from JS, honoring the wasm tag.
+//
+// The wasm tag is formatted as "name,option,option...". If the name is empty, the Go field name is used. If the tag is
+// "-", the field is skipped. The following options are supported:
+//
+// omitempty: the field is not set on the JS object if it holds the zero value of its kind.
+//
+// string: booleans and numbers (or pointers to them) are converted to and from JS strings.
+//
+// required: decoding fails if the property is missing or undefined on the JS object.
+//
+// jsmap: the map is converted to a JS Map instead of a plain object.
+//
+// bigint: 64-bit integers and big.Int (or pointers to them) are converted to JS BigInts.
+//
+// Fields without a tag name are named by the NamingPolicy of the struct type, which applies to the fields promoted
+// from embedded structs as well.
+//
+// The fields of embedded structs without a tag name are promoted into the parent, following the rules of
+// encoding/json: the shallowest field wins, then the tagged one, and fields that still conflict are dropped.
+func typeFields(t reflect.Type) []field {
+	naming := namingPolicy(t)
+
+	// Embedded structs to explore at the current level and the next.
+	current := []field{}
+	next := []field{{typ: t}}
+
+	// Count of queued names for the current level and the next.
+	var count, nextCount map[reflect.Type]int
+
+	// Types already visited at an earlier level.
+	visited := map[reflect.Type]bool{}
+
+	var fields []field
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					t := sf.Type
+					if t.Kind() == reflect.Ptr {
+						t = t.Elem()
+					}
+					// Embedded unexported structs may still hold exported fields.
+					if sf.PkgPath != "" && t.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+
+				tag := sf.Tag.Get("wasm")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					// Explore the embedded struct in the next round.
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, field{name: ft.Name(), index: index, typ: ft})
+					}
+					continue
+				}
+
+				field := field{
+					name:      name,
+					tag:       name != "",
+					index:     index,
+					typ:       sf.Type,
+					goName:    sf.Name,
+					omitEmpty: opts.Contains("omitempty"),
+					required:  opts.Contains("required"),
+					jsMap:     opts.Contains("jsmap"),
+					bigInt:    opts.Contains("bigint"),
+				}
+				if name == "" {
+					field.name = naming.name(sf.Name)
+				}
+
+				if opts.Contains("string") {
+					switch ft.Kind() {
+					case reflect.Bool,
+						reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+						reflect.Float32, reflect.Float64:
+						field.quoted = true
+					}
+				}
+
+				fields = append(fields, field)
+				if count[f.typ] > 1 {
+					// The same struct is embedded more than once at this level, so add a duplicate for the
+					// conflict resolution below to drop the field.
+					fields = append(fields, fields[len(fields)-1])
+				}
+			}
+		}
+	}
+
+	// Sort by name, breaking ties with depth, then with the presence of a tag, then with the index sequence.
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		if a.tag != b.tag {
+			return a.tag
+		}
+		return indexLess(a.index, b.index)
+	})
+
+	// Keep the dominant field for every name.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		name := fields[i].name
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != name {
+				break
+			}
+		}
+
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	fields = out
+	sort.Slice(fields, func(i, j int) bool {
+		return indexLess(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+// dominantField returns the field that hides the others with the same name.
+// The fields must be sorted by depth and then by the presence of a tag. If the first two fields are at the same depth
+// and are either both tagged or both untagged, there is no dominant field and it returns false.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tag == fields[1].tag {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+// indexLess compares two index sequences lexicographically.
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// fieldByIndex returns the nested field of the provided struct at the index sequence.
+// Nil embedded pointers are allocated if alloc is true. Otherwise, an invalid reflect.Value is returned when one is
+// encountered.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, nil
+				}
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s",
+						v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// tagOptions is the string following a comma in a struct field's wasm tag, or the empty string.
+type tagOptions string
+
+// parseTag splits a struct field's wasm tag into its name and comma-separated options.
+func parseTag(tag string) (string, tagOptions) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tagOptions(tag[idx+1:])
+	}
+	return tag, ""
+}
+
+// Contains reports whether a comma-separated list of options contains a particular option.
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == option {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+// isEmptyValue reports whether v holds the zero value of its kind, as used by the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
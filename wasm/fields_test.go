@@ -0,0 +1,147 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+type embeddedA struct {
+	A      int
+	Shared int
+	Tagged int `wasm:"tagged"`
+}
+
+type embeddedB struct {
+	B      int
+	Shared int
+	Tagged int
+}
+
+type embeddedDeep struct {
+	embeddedA
+}
+
+type promotedFields struct {
+	embeddedA
+	*embeddedB
+	Own int `wasm:"own,omitempty"`
+}
+
+type shadowedFields struct {
+	embeddedDeep
+	A int
+}
+
+type duplicatedFields struct {
+	embeddedA
+	Other struct{ embeddedA }
+}
+
+type namedEmbedded struct {
+	embeddedA `wasm:"inner"`
+	Skipped   int `wasm:"-"`
+	private   int
+}
+
+func TestTypeFields(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want map[string][]int
+	}{
+		{
+			name: "conflicting untagged fields are dropped",
+			typ:  reflect.TypeOf(promotedFields{}),
+			want: map[string][]int{
+				"A":      {0, 0},
+				"tagged": {0, 2},
+				"B":      {1, 0},
+				"Tagged": {1, 2},
+				"own":    {2},
+			},
+		},
+		{
+			name: "shallower field wins",
+			typ:  reflect.TypeOf(shadowedFields{}),
+			want: map[string][]int{
+				"Shared": {0, 0, 1},
+				"tagged": {0, 0, 2},
+				"A":      {1},
+			},
+		},
+		{
+			name: "fields of a struct field are not promoted",
+			typ:  reflect.TypeOf(duplicatedFields{}),
+			want: map[string][]int{
+				"A":      {0, 0},
+				"Shared": {0, 1},
+				"tagged": {0, 2},
+				"Other":  {1},
+			},
+		},
+		{
+			name: "tagged embedded struct is a field",
+			typ:  reflect.TypeOf(namedEmbedded{}),
+			want: map[string][]int{
+				"inner": {0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string][]int{}
+			for _, f := range typeFields(tt.typ) {
+				got[f.name] = f.index
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("typeFields(%s) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDominantField(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []field
+		want   []int
+		ok     bool
+	}{
+		{
+			name:   "single field",
+			fields: []field{{index: []int{0, 1}}},
+			want:   []int{0, 1},
+			ok:     true,
+		},
+		{
+			name:   "shallower field",
+			fields: []field{{index: []int{1}}, {index: []int{0, 1}}},
+			want:   []int{1},
+			ok:     true,
+		},
+		{
+			name:   "tagged field at the same depth",
+			fields: []field{{index: []int{1, 0}, tag: true}, {index: []int{0, 1}}},
+			want:   []int{1, 0},
+			ok:     true,
+		},
+		{
+			name:   "untagged fields at the same depth",
+			fields: []field{{index: []int{0, 1}}, {index: []int{1, 0}}},
+		},
+		{
+			name:   "tagged fields at the same depth",
+			fields: []field{{index: []int{0, 1}, tag: true}, {index: []int{1, 0}, tag: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := dominantField(tt.fields)
+			if ok != tt.ok || ok && !reflect.DeepEqual(got.index, tt.want) {
+				t.Errorf("dominantField() = %v, %t, want %v, %t", got.index, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package wasm
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall/js"
+)
+
+// Func is a Go function converted to a JS function.
+//
+// Calling a Go function from JS requires resources that are only freed once the function is released. Functions
+// converted by this package are released automatically once they are garbage collected, when the JS engine supports
+// FinalizationRegistry, but garbage collection is not guaranteed to happen. Release frees them up explicitly, which
+// should be preferred for functions that are converted over and over again.
+//
+// The zero value of this struct is not a valid Func.
+type Func struct {
+	value js.Value
+	id    uint64
+}
+
+// FuncOf converts the provided Go function, or a function marked with Async, to a JS function like ToJSValue does.
+// It returns a handle to release the function.
+// It panics if fn is not a function.
+func FuncOf(fn interface{}) Func {
+	if a, ok := fn.(asyncFunc); ok {
+		return toAsyncJSFunc(a.fn, defaultEncoder.opts)
+	}
+
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic("cannot convert " + reflect.TypeOf(fn).String() + " to a JS function as it is not a function")
+	}
+	return toJSFunc(v, defaultEncoder.opts)
+}
+
+// JSValue implements the Wrapper interface.
+func (f Func) JSValue() js.Value {
+	return f.value
+}
+
+// Release frees up the resources of the function. Calling it from JS afterwards throws an error.
+// Releasing a function more than once does nothing.
+func (f Func) Release() {
+	if f.id == 0 {
+		return
+	}
+
+	if registry := finalizationRegistry(); !registry.IsUndefined() {
+		registry.Call("unregister", f.value)
+	}
+	releaseTracked(f.id)
+}
+
+// LiveFuncs returns the number of JS functions created by this package that are not released yet.
+// It is meant for debugging, such as to find functions that are converted over and over again without being freed.
+func LiveFuncs() int {
+	return int(atomic.LoadInt64(&liveFuncs))
+}
+
+var (
+	// liveFuncs counts the js.Funcs created with newFunc that are not released yet.
+	liveFuncs int64
+
+	// trackedFuncs holds the js.Funcs of every Func that is not released yet, by the ID of the Func.
+	trackedFuncs sync.Map // map[uint64][]js.Func
+	lastFuncID   uint64
+
+	registryOnce sync.Once
+	registry     js.Value
+)
+
+// newFunc creates a js.Func like js.FuncOf, counting it in LiveFuncs until it is released with releaseFunc.
+func newFunc(fn func(this js.Value, args []js.Value) interface{}) js.Func {
+	atomic.AddInt64(&liveFuncs, 1)
+	return js.FuncOf(fn)
+}
+
+// releaseFunc releases a js.Func created with newFunc.
+func releaseFunc(f js.Func) {
+	f.Release()
+	atomic.AddInt64(&liveFuncs, -1)
+}
+
+// trackFuncs returns a Func for the provided JS value, which releases the provided js.Funcs once it is released or
+// garbage collected. The js.Funcs must not hold the JS value, or it is never garbage collected.
+func trackFuncs(value js.Value, fs ...js.Func) Func {
+	f := Func{
+		value: value,
+		id:    atomic.AddUint64(&lastFuncID, 1),
+	}
+	trackedFuncs.Store(f.id, fs)
+
+	if registry := finalizationRegistry(); !registry.IsUndefined() {
+		// The value doubles as the unregister token, which the registry holds weakly as well.
+		registry.Call("register", value, float64(f.id), value)
+	}
+	return f
+}
+
+// releaseTracked releases the js.Funcs tracked under the provided ID, if they are not released yet.
+func releaseTracked(id uint64) {
+	fs, ok := trackedFuncs.LoadAndDelete(id)
+	if !ok {
+		return
+	}
+	for _, f := range fs.([]js.Func) {
+		releaseFunc(f)
+	}
+}
+
+// finalizationRegistry returns the FinalizationRegistry that releases the tracked js.Funcs, creating it once.
+// It returns undefined if the JS engine does not support FinalizationRegistry.
+func finalizationRegistry() js.Value {
+	registryOnce.Do(func() {
+		constructor := js.Global().Get("FinalizationRegistry")
+		if constructor.Type() != js.TypeFunction {
+			registry = js.Undefined()
+			return
+		}
+
+		// The cleanup function lives as long as the registry, so it is never released.
+		cleanup := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			releaseTracked(uint64(args[0].Float()))
+			return nil
+		})
+		registry = constructor.New(cleanup)
+	})
+	return registry
+}
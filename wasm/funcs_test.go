@@ -0,0 +1,70 @@
+package wasm
+
+import (
+	"testing"
+	"time"
+)
+
+// waitLiveFuncs waits for LiveFuncs to reach the provided count, as some functions are released in the background.
+func waitLiveFuncs(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for LiveFuncs() != want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := LiveFuncs(); got != want {
+		t.Errorf("LiveFuncs() = %d, want %d", got, want)
+	}
+}
+
+func TestFuncRelease(t *testing.T) {
+	base := LiveFuncs()
+
+	f := FuncOf(func(n int) int { return n * 2 })
+	waitLiveFuncs(t, base+1)
+	if got := f.JSValue().Invoke(2).Int(); got != 4 {
+		t.Errorf("f(2) = %d, want 4", got)
+	}
+
+	f.Release()
+	waitLiveFuncs(t, base)
+
+	// Releasing again, or the finalizer running afterwards, must not release the function twice.
+	f.Release()
+	releaseTracked(f.id)
+	waitLiveFuncs(t, base)
+}
+
+func TestFuncReleaseUnregisters(t *testing.T) {
+	registry := finalizationRegistry()
+	if registry.IsUndefined() {
+		t.Skip("FinalizationRegistry is not supported")
+	}
+
+	released := FuncOf(func() {})
+	released.Release()
+	if registry.Call("unregister", released.JSValue()).Bool() {
+		t.Error("Release did not unregister the function from the FinalizationRegistry")
+	}
+
+	live := FuncOf(func() {})
+	defer live.Release()
+	if !registry.Call("unregister", live.JSValue()).Bool() {
+		t.Error("FuncOf did not register the function with the FinalizationRegistry")
+	}
+}
+
+func TestPromiseReleasesFuncs(t *testing.T) {
+	base := LiveFuncs()
+
+	var n int
+	if err := NewPromise(func() (interface{}, error) { return 1, nil }).Await(&n); err != nil {
+		t.Fatalf("Await returned error: %v", err)
+	}
+	waitLiveFuncs(t, base)
+
+	if err := NewPromise(func() (interface{}, error) { panic("boom") }).Await(nil); err == nil {
+		t.Error("Await of a panicking handler returned no error")
+	}
+	waitLiveFuncs(t, base)
+}
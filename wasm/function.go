@@ -1,14 +1,59 @@
 package wasm
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
 	"syscall/js"
 )
 
 // ErrInvalidArgumentType is returned when a generated Go function wrapper receives invalid argument types from JS.
+// It is wrapped by the ArgumentError returned when JS passes too few or too many arguments.
 var ErrInvalidArgumentType = errors.New("invalid argument passed into Go function")
 
+// ArgumentError is an error where JS calls a Go function with an argument that does not match its parameters.
+type ArgumentError struct {
+	// Func is the name of the Go function, such as main.search, or its type if it has no name, such as for methods.
+	Func string
+	// Index is the position of the argument among the ones passed by JS, starting at 0.
+	Index int
+	// GoType is the type of the parameter of the argument. It is nil if JS passes too many arguments.
+	GoType reflect.Type
+	// JSType is the type of the argument as named by js.Type, such as "string", or "undefined" if it is missing.
+	JSType string
+	// Err is the error returned when decoding the argument, or ErrInvalidArgumentType if JS passes too few or too many
+	// arguments.
+	Err error
+}
+
+// Error implements error.
+func (e *ArgumentError) Error() string {
+	msg := fmt.Sprintf("invalid argument %d passed into Go function %s: ", e.Index, e.Func)
+	switch {
+	case e.Err != nil && !errors.Is(e.Err, ErrInvalidArgumentType):
+		return msg + e.Err.Error()
+	case e.GoType == nil:
+		return msg + "unexpected " + e.JSType
+	}
+	return msg + "expected " + e.GoType.String() + " but got " + e.JSType
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// funcName returns the name of the provided Go function, or its type if it has no name.
+func funcName(x reflect.Value) string {
+	fn := runtime.FuncForPC(x.Pointer())
+	if fn == nil || fn.Name() == "reflect.methodValueCall" {
+		return x.Type().String()
+	}
+	return fn.Name()
+}
+
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
 
 type goThrowable struct {
@@ -19,74 +64,143 @@ type goThrowable struct {
 // toJSFunc takes a reflect.Value of a Go function and converts it to a JS function that:
 // Errors if the parameter types do not conform to the Go function signature,
 // Throws an error if the last returned value is an error and is non-nil,
+// Throws a PanicError if the Go function panics,
+// Passes a context if the first parameter is a context.Context, which is only cancelled if the AbortSignal from JS
+// aborted before the call, as the function blocks the event loop that would dispatch a later abort,
 // Return an array if there's multiple non-error return values.
-func toJSFunc(x reflect.Value) js.Value {
+// Arguments are decoded and return values are encoded according to the provided options.
+func toJSFunc(x reflect.Value, opts EncoderOptions) Func {
 	funcType := x.Type()
-	var hasError bool
-	if funcType.NumOut() != 0 {
-		hasError = funcType.Out(funcType.NumOut()-1) == errorType
-	}
+	return throwingFunc(func(this js.Value, args []js.Value) (js.Value, error) {
+		ctx, args, cancel := signalContext(funcType, args, false)
+		defer cancel()
 
-	return funcWrapper.Invoke(js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		in, err := conformJSValueToType(funcType, this, args)
+		in, err := conformJSValueToType(x, ctx, this, args, NewDecoder(opts.Arguments))
 		if err != nil {
-			return ToJSValue(goThrowable{
-				Error: NewError(err),
-			})
+			return js.Undefined(), err
 		}
+		return callFunc(x, in, opts)
+	})
+}
 
-		out := x.Call(in)
-
-		if !hasError {
-			return ToJSValue(goThrowable{
-				Result: returnValue(out),
-			})
-		}
+// callFunc calls the provided Go function and converts its return values to JS with the provided options.
+// A non-nil error returned last by the function is returned instead.
+func callFunc(x reflect.Value, in []reflect.Value, opts EncoderOptions) (js.Value, error) {
+	funcType := x.Type()
+	out := x.Call(in)
 
+	if funcType.NumOut() != 0 && funcType.Out(funcType.NumOut()-1) == errorType {
 		lastParam := out[len(out)-1]
 		if !lastParam.IsNil() {
+			return js.Undefined(), lastParam.Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+
+	return returnValue(out, NewEncoder(opts))
+}
+
+// throwingFunc converts the provided function to a JS function that returns its result, or throws its error.
+// Panics are recovered and thrown as a PanicError, so that the Go program keeps running.
+func throwingFunc(fn func(this js.Value, args []js.Value) (js.Value, error)) Func {
+	f := newFunc(func(this js.Value, args []js.Value) interface{} {
+		result, err := callRecovered(fn, this, args)
+		if err != nil {
 			return ToJSValue(goThrowable{
-				Error: NewError(lastParam.Interface().(error)),
+				Error: NewError(err),
 			})
 		}
 		return ToJSValue(goThrowable{
-			Result: returnValue(out[:len(out)-1]),
+			Result: result,
 		})
-	}))
+	})
+	return trackFuncs(funcWrapper.Invoke(f), f)
+}
+
+// callRecovered calls the provided function, returning a PanicError if it panics.
+func callRecovered(
+	fn func(this js.Value, args []js.Value) (js.Value, error), this js.Value, args []js.Value,
+) (result js.Value, err error) {
+	defer recoverPanic(&err)
+	return fn(this, args)
 }
 
 var jsValueType = reflect.TypeOf(js.Value{})
 
 // conformJSValueToType attempts to convert the provided JS values to reflect.Values that match the
-// types expected for the parameters of funcType.
-func conformJSValueToType(funcType reflect.Type, this js.Value, values []js.Value) ([]reflect.Value, error) {
-	if funcType.NumIn() == 0 {
-		if len(values) != 0 {
-			return nil, ErrInvalidArgumentType
-		}
-		return []reflect.Value{}, nil
+// types expected for the parameters of the Go function x, using the provided ValueDecoder.
+// If the first parameter is a context.Context, it is set to the provided context.
+// Trailing parameters that can be omitted, as described by RegisterDefault, are set to their default when JS omits
+// them or passes undefined.
+// It returns an ArgumentError if an argument is missing, unexpected or cannot be decoded.
+func conformJSValueToType(
+	x reflect.Value, ctx context.Context, this js.Value, values []js.Value, dec *ValueDecoder,
+) ([]reflect.Value, error) {
+	funcType := x.Type()
+	in := make([]reflect.Value, 0, funcType.NumIn())
+	first := 0
+	if funcType.NumIn() != 0 && funcType.In(0) == contextType {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+		first = 1
 	}
 
-	if funcType.In(0) == jsValueType {
+	numIn := funcType.NumIn() - first
+	offset := 0
+	if numIn != 0 && funcType.In(first) == jsValueType {
 		// If the first parameter is a js.Value, it is assumed to be the value of `this`.
 		values = append([]js.Value{this}, values...)
+		offset = 1
 	}
 
-	if funcType.IsVariadic() && funcType.NumIn()-1 > len(values) {
-		return nil, ErrInvalidArgumentType
+	// Parameters before the variadic one, which can be omitted as well.
+	fixed := numIn
+	if funcType.IsVariadic() {
+		fixed--
 	}
-
-	if !funcType.IsVariadic() && funcType.NumIn() != len(values) {
-		return nil, ErrInvalidArgumentType
+	required := requiredParams(funcType, first, fixed)
+
+	if len(values) < required {
+		return nil, &ArgumentError{
+			Func:   funcName(x),
+			Index:  len(values) - offset,
+			GoType: funcType.In(first + len(values)),
+			JSType: js.TypeUndefined.String(),
+			Err:    ErrInvalidArgumentType,
+		}
+	}
+	if !funcType.IsVariadic() && len(values) > numIn {
+		return nil, &ArgumentError{
+			Func:   funcName(x),
+			Index:  numIn - offset,
+			JSType: jsTypeName(values[numIn]),
+			Err:    ErrInvalidArgumentType,
+		}
 	}
 
-	in := make([]reflect.Value, 0, len(values))
-	for i, v := range values {
-		paramType := funcType.In(i)
+	for i := 0; i < fixed || i < len(values); i++ {
+		var paramType reflect.Type
+		if i < fixed {
+			paramType = funcType.In(first + i)
+		} else {
+			paramType = funcType.In(funcType.NumIn() - 1).Elem()
+		}
+
+		if i >= required && i < fixed && (i >= len(values) || values[i].IsUndefined()) {
+			def, _ := paramDefault(paramType)
+			in = append(in, def)
+			continue
+		}
+
 		ptrX := reflect.New(paramType).Interface()
-		err := FromJSValue(v, ptrX)
+		err := dec.FromJSValue(values[i], ptrX)
 		if err != nil {
-			return nil, err
+			return nil, &ArgumentError{
+				Func:   funcName(x),
+				Index:  i - offset,
+				GoType: paramType,
+				JSType: jsTypeName(values[i]),
+				Err:    err,
+			}
 		}
 
 		in = append(in, reflect.ValueOf(ptrX).Elem())
@@ -99,12 +213,13 @@ func conformJSValueToType(funcType reflect.Type, this js.Value, values []js.Valu
 // If there are no returned values, it returns undefined.
 // If there is exactly one, it returns the JS equivalent.
 // If there is more than one, it returns an array containing the JS equivalent of every returned value.
-func returnValue(x []reflect.Value) js.Value {
+// It returns a CycleError if a value refers back to itself.
+func returnValue(x []reflect.Value, enc *Encoder) (js.Value, error) {
 	switch len(x) {
 	case 0:
-		return js.Undefined()
+		return js.Undefined(), nil
 	case 1:
-		return ToJSValue(x[0].Interface())
+		return enc.Encode(x[0].Interface())
 	}
 
 	xInterface := make([]interface{}, 0, len(x))
@@ -112,5 +227,5 @@ func returnValue(x []reflect.Value) js.Value {
 		xInterface = append(xInterface, v.Interface())
 	}
 
-	return ToJSValue(xInterface)
+	return enc.Encode(xInterface)
 }
@@ -0,0 +1,48 @@
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestArgumentErrorError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ArgumentError
+		want string
+	}{
+		{
+			"missing",
+			&ArgumentError{Func: "main.search", Index: 1, GoType: reflect.TypeOf(0), JSType: "undefined",
+				Err: ErrInvalidArgumentType},
+			"invalid argument 1 passed into Go function main.search: expected int but got undefined",
+		},
+		{
+			"unexpected",
+			&ArgumentError{Func: "main.search", Index: 2, JSType: "string", Err: ErrInvalidArgumentType},
+			"invalid argument 2 passed into Go function main.search: unexpected string",
+		},
+		{
+			"wrapped sentinel",
+			&ArgumentError{Func: "main.search", Index: 2, JSType: "string",
+				Err: fmt.Errorf("checking arguments: %w", ErrInvalidArgumentType)},
+			"invalid argument 2 passed into Go function main.search: unexpected string",
+		},
+		{
+			"decoding",
+			&ArgumentError{Func: "main.search", Index: 0, GoType: reflect.TypeOf(0), JSType: "string",
+				Err: errors.New("cannot decode")},
+			"invalid argument 0 passed into Go function main.search: cannot decode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
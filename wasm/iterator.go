@@ -0,0 +1,319 @@
+package wasm
+
+import (
+	"reflect"
+	"sync"
+	"syscall/js"
+)
+
+// jsIterations holds the state of every Go channel fed by a JS iterable, until the iteration completes successfully
+// or StopIterator is called. Keys are the channels themselves, see chanKey.
+var jsIterations sync.Map // map[interface{}]*jsIteration
+
+// chanIterations holds the channel closed once JS stops iterating over a Go channel, for every channel passed to
+// IteratorStopped, until the channel is closed or the iteration is stopped. Keys are the channels themselves.
+var chanIterations sync.Map // map[interface{}]chan struct{}
+
+// chanKey returns the key identifying the provided channel in jsIterations and chanIterations.
+// The channel is converted to its receive-only type, so that the same channel gives the same key whatever its
+// direction. Unlike its address, the channel keeps its entry from being confused with a channel allocated later.
+func chanKey(v reflect.Value) (interface{}, bool) {
+	if v.Kind() != reflect.Chan || v.IsNil() || v.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, false
+	}
+	return v.Convert(reflect.ChanOf(reflect.RecvDir, v.Type().Elem())).Interface(), true
+}
+
+// jsIteration is the state of a Go channel fed by a JS iterable.
+type jsIteration struct {
+	// stop is closed by StopIterator.
+	stop chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// fail records the error that ended the iteration.
+func (it *jsIteration) fail(err error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	it.err = err
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *jsIteration) Err() error {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.err
+}
+
+// StopIterator stops reading from the JS iterable that the provided channel was decoded from, and returns the error
+// that ended the iteration, if any: a *JSError when a promise of the iterator rejects, or the error of a value that
+// cannot be decoded. Stopping an iteration that is still running is not an error.
+//
+// The return method of the JS iterator is called, as a for await loop would do on break, and the channel is closed
+// once the value being sent, if any, is dropped. A consumer that stops reading from the channel without calling
+// StopIterator leaves the goroutine feeding it blocked, and the JS iterator open, for the rest of the program.
+//
+// Once the channel is closed, StopIterator only reports the error, so it should be called to tell an iteration that
+// failed apart from one that completed. It returns nil if the channel is not fed by a JS iterable.
+func StopIterator(ch interface{}) error {
+	key, ok := chanKey(reflect.ValueOf(ch))
+	if !ok {
+		return nil
+	}
+
+	x, ok := jsIterations.LoadAndDelete(key)
+	if !ok {
+		return nil
+	}
+	it := x.(*jsIteration)
+	close(it.stop)
+	return it.Err()
+}
+
+// IteratorStopped returns a channel that is closed once JS stops iterating early over the provided Go channel, by
+// breaking out of a for await loop or calling the return method of the iterator. No more values are received from
+// the channel afterwards, so a producer should stop sending when it is closed, and close the channel:
+//
+//	ch := make(chan int)
+//	stopped := wasm.IteratorStopped(ch)
+//	go func() {
+//		defer close(ch)
+//		for i := 0; ; i++ {
+//			select {
+//			case ch <- i:
+//			case <-stopped:
+//				return
+//			}
+//		}
+//	}()
+//
+// It must be called before the channel is converted to JS, and its result kept: the channel is forgotten once the
+// iteration is stopped, or once JS receives that the channel is closed. It returns nil if ch is not a channel that
+// can be received from.
+func IteratorStopped(ch interface{}) <-chan struct{} {
+	key, ok := chanKey(reflect.ValueOf(ch))
+	if !ok {
+		return nil
+	}
+
+	stopped, _ := chanIterations.LoadOrStore(key, make(chan struct{}))
+	return stopped.(chan struct{})
+}
+
+// newChanEncoder returns an encoderFunc that converts a channel to a JS async iterable.
+// Channels that cannot be received from are not supported.
+func newChanEncoder(t reflect.Type) encoderFunc {
+	if t.ChanDir()&reflect.RecvDir == 0 {
+		return unsupportedTypeEncoder
+	}
+
+	return func(e *encodeState, v reflect.Value) js.Value {
+		if v.IsNil() {
+			return js.Undefined()
+		}
+		return toJSAsyncIterable(v, e.opts)
+	}
+}
+
+// chanIterator implements the JS async iterator protocol on top of a Go channel.
+// Values are received from the channel only when JS asks for them, so a producer blocked on an unbuffered channel
+// waits for the consumer.
+type chanIterator struct {
+	ch   reflect.Value
+	opts EncoderOptions
+
+	// turn is closed once the previous call to next has settled, which keeps the results in order.
+	turn chan struct{}
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// toJSAsyncIterable converts the provided channel to a JS async iterable, encoding every received value with the
+// provided options. The iteration is done when the channel is closed.
+func toJSAsyncIterable(ch reflect.Value, opts EncoderOptions) js.Value {
+	turn := make(chan struct{})
+	close(turn)
+
+	it := &chanIterator{
+		ch:   ch,
+		opts: opts,
+		turn: turn,
+	}
+
+	next := newFunc(it.next)
+	stop := newFunc(it.stop)
+	// The iterator is returned as this, so that the function does not hold the iterator.
+	self := newFunc(func(this js.Value, args []js.Value) interface{} {
+		return this
+	})
+
+	iterator := globalConstructor("Object").New()
+	iterator.Set("next", next)
+	iterator.Set("return", stop)
+	defineSymbolProperty(iterator, wellKnownSymbol("asyncIterator"), self.Value)
+
+	// The functions are released once the iterator is garbage collected.
+	trackFuncs(iterator, next, stop, self)
+	return iterator
+}
+
+// next receives the next value from the channel, returning a Promise of the iterator result.
+func (it *chanIterator) next(this js.Value, args []js.Value) interface{} {
+	wait := it.turn
+	turn := make(chan struct{})
+	it.turn = turn
+
+	return NewPromise(func() (interface{}, error) {
+		<-wait
+		defer close(turn)
+
+		if it.isStopped() {
+			return iteratorResult(js.Undefined(), true), nil
+		}
+
+		x, ok := it.ch.Recv()
+		if !ok {
+			it.markStopped()
+			if key, ok := chanKey(it.ch); ok {
+				chanIterations.Delete(key)
+			}
+			return iteratorResult(js.Undefined(), true), nil
+		}
+
+		value, err := NewEncoder(it.opts).Encode(x.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return iteratorResult(value, false), nil
+	}).JSValue()
+}
+
+// stop implements the return method of the iterator, which JS calls when a for await loop exits early.
+// No more values are received from the channel afterwards, and the channel returned by IteratorStopped is closed.
+func (it *chanIterator) stop(this js.Value, args []js.Value) interface{} {
+	if it.markStopped() {
+		if key, ok := chanKey(it.ch); ok {
+			if stopped, ok := chanIterations.LoadAndDelete(key); ok {
+				close(stopped.(chan struct{}))
+			}
+		}
+	}
+
+	return NewPromise(func() (interface{}, error) {
+		return iteratorResult(js.Undefined(), true), nil
+	}).JSValue()
+}
+
+func (it *chanIterator) isStopped() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.stopped
+}
+
+// markStopped marks the iteration as stopped, returning false if it already was.
+func (it *chanIterator) markStopped() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if it.stopped {
+		return false
+	}
+	it.stopped = true
+	return true
+}
+
+// iteratorResult creates the object returned by the next method of a JS iterator.
+func iteratorResult(value js.Value, done bool) js.Value {
+	result := globalConstructor("Object").New()
+	result.Set("value", value)
+	result.Set("done", done)
+	return result
+}
+
+// newChanDecoder returns a decoderFunc that decodes a JS async iterable, or a regular iterable, into a channel.
+// Channels that cannot be received from are not supported.
+func newChanDecoder(t reflect.Type) decoderFunc {
+	if t.ChanDir()&reflect.RecvDir == 0 {
+		return decodeUnsupported
+	}
+
+	elemDec := typeDecoder(t.Elem())
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		iterator, ok := jsIterator(x)
+		if !ok {
+			return InvalidTypeError{x.Type(), v.Type()}
+		}
+
+		ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.Elem()), 0)
+		key, _ := chanKey(ch)
+		it := &jsIteration{stop: make(chan struct{})}
+		jsIterations.Store(key, it)
+
+		go iterateJS(iterator, ch, it, elemDec, d.opts)
+		v.Set(ch)
+		return nil
+	}
+}
+
+// jsIterator returns the iterator of the provided JS value, preferring its async iterator over its regular one.
+func jsIterator(x js.Value) (js.Value, bool) {
+	if x.Type() != js.TypeObject && x.Type() != js.TypeFunction {
+		return js.Value{}, false
+	}
+
+	for _, name := range []string{"asyncIterator", "iterator"} {
+		method := getSymbolProperty(x, wellKnownSymbol(name))
+		if method.Type() == js.TypeFunction {
+			return method.Call("call", x), true
+		}
+	}
+	return js.Value{}, false
+}
+
+// iterateJS sends the values of the provided JS iterator to the channel, decoding them with elemDec.
+// A value is only requested from JS once the previous one is received in Go.
+// The channel is closed when the iterator is done, when it rejects, when a value cannot be decoded or when the
+// iteration is stopped. The JS iterator is asked to clean up in the last two cases. Errors are recorded in it for
+// StopIterator, while an iteration that completes successfully forgets it.
+func iterateJS(iterator js.Value, ch reflect.Value, it *jsIteration, elemDec decoderFunc, opts DecoderOptions) {
+	defer ch.Close()
+	key, _ := chanKey(ch)
+
+	for i := 0; ; i++ {
+		var result js.Value
+		next := globalConstructor("Promise").Call("resolve", iterator.Call("next"))
+		if err := mustJSValueToPromise(next).Await(&result); err != nil {
+			it.fail(err)
+			return
+		}
+		if result.Type() != js.TypeObject || result.Get("done").Truthy() {
+			jsIterations.Delete(key)
+			return
+		}
+
+		elem := reflect.New(ch.Type().Elem()).Elem()
+		if err := elemDec(&decodeState{opts: opts}, result.Get("value"), elem); err != nil {
+			it.fail(withIndex(err, i, result.Get("value"), ch.Type().Elem()))
+			closeJSIterator(iterator)
+			return
+		}
+
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: ch, Send: elem},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(it.stop)},
+		})
+		if chosen == 1 {
+			closeJSIterator(iterator)
+			return
+		}
+	}
+}
+
+// closeJSIterator calls the return method of the provided JS iterator if it has one.
+func closeJSIterator(iterator js.Value) {
+	if iterator.Get("return").Type() == js.TypeFunction {
+		iterator.Call("return")
+	}
+}
@@ -0,0 +1,149 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeIterable(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    []int
+		wantErr interface{}
+	}{
+		{"array", `[1, 2, 3]`, []int{1, 2, 3}, nil},
+		{"async generator", `(async function*() { yield 1; yield 2 })()`, []int{1, 2}, nil},
+		{"rejection", `(async function*() { yield 1; throw new Error("boom") })()`, []int{1}, new(*JSError)},
+		{"invalid value", `[1, "two", 3]`, []int{1}, new(*DecodeError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ch <-chan int
+			if err := FromJSValue(evalJS(tt.expr), &ch); err != nil {
+				t.Fatalf("FromJSValue returned error: %v", err)
+			}
+
+			var got []int
+			for n := range ch {
+				got = append(got, n)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+
+			err := StopIterator(ch)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("StopIterator returned error: %v", err)
+			case tt.wantErr != nil && !errors.As(err, tt.wantErr):
+				t.Errorf("StopIterator returned %v, want a %T", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStopIterator(t *testing.T) {
+	returned := evalJS(`{called: false}`)
+	newIterable := evalJS(`(state) => (function*() { try { for (let i = 0; ; i++) yield i } finally { state.called = true } })()`)
+
+	var ch <-chan int
+	if err := FromJSValue(newIterable.Invoke(returned), &ch); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	if n := <-ch; n != 0 {
+		t.Fatalf("received %d, want 0", n)
+	}
+
+	if err := StopIterator(ch); err != nil {
+		t.Errorf("StopIterator returned error: %v", err)
+	}
+	for range ch {
+	}
+	if !returned.Get("called").Bool() {
+		t.Error("StopIterator did not call the return method of the iterator")
+	}
+}
+
+func TestIteratorStopped(t *testing.T) {
+	ch := make(chan int)
+	stopped := IteratorStopped(ch)
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		defer close(ch)
+		for i := 0; ; i++ {
+			select {
+			case ch <- i:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	takeThree := evalJS(`async (it) => { const got = []; for await (const x of it) { got.push(x); if (got.length === 3) break } return got }`)
+	var got []int
+	if err := mustJSValueToPromise(takeThree.Invoke(ToJSValue((<-chan int)(ch)))).Await(&got); err != nil {
+		t.Fatalf("Await returned error: %v", err)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("JS received %v, want %v", got, want)
+	}
+
+	select {
+	case <-producerDone:
+	case <-time.After(time.Second):
+		t.Fatal("the producer was not stopped after JS broke out of the loop")
+	}
+	if _, ok := chanIterations.Load((<-chan int)(ch)); ok {
+		t.Error("IteratorStopped did not forget the stopped channel")
+	}
+}
+
+func TestIteratorStoppedClosed(t *testing.T) {
+	ch := make(chan int, 2)
+	stopped := IteratorStopped(ch)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	collect := evalJS(`async (it) => { const got = []; for await (const x of it) got.push(x); return got }`)
+	var got []int
+	if err := mustJSValueToPromise(collect.Invoke(ToJSValue(ch))).Await(&got); err != nil {
+		t.Fatalf("Await returned error: %v", err)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("JS received %v, want %v", got, want)
+	}
+
+	select {
+	case <-stopped:
+		t.Error("IteratorStopped was closed although JS did not stop early")
+	default:
+	}
+	if _, ok := chanIterations.Load((<-chan int)(ch)); ok {
+		t.Error("IteratorStopped did not forget the closed channel")
+	}
+}
+
+func TestStopIteratorUnknownChannel(t *testing.T) {
+	var ch <-chan int
+	if err := FromJSValue(evalJS(`(async function*() { throw new Error("boom") })()`), &ch); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	for range ch {
+	}
+
+	if err := StopIterator(make(chan int)); err != nil {
+		t.Errorf("StopIterator of an unrelated channel returned %v", err)
+	}
+	if err := StopIterator(ch); !errors.As(err, new(*JSError)) {
+		t.Errorf("StopIterator returned %v, want a *JSError", err)
+	}
+	if err := StopIterator(ch); err != nil {
+		t.Errorf("StopIterator called twice returned %v", err)
+	}
+}
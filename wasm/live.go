@@ -0,0 +1,260 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"syscall/js"
+)
+
+// ExposeLive exposes the struct pointed to by x in JS as a Proxy bound to it, unlike Expose which exposes a copy.
+//
+// Reading a property of the Proxy converts the current value of the matching field to JS, and setting a property
+// converts the JS value back into the field, throwing an error if it cannot be converted. Fields are named and
+// converted as with ToJSValue and FromJSValue. Nested structs, and pointers to them, are exposed as Proxies as well,
+// so that their fields are bound too. Methods and accessor properties are exposed according to the MethodOptions of
+// the struct.
+//
+// Only structs are bound. Slices, arrays and maps read through the Proxy are copies, so modifying them in JS, such as
+// with proxy.items.push(x) or proxy.m.k = v, does not change the Go value. Assign the whole property instead, as in
+// proxy.items = [...proxy.items, x], which converts it back into the field.
+//
+// The Proxy is accessed from the JS event loop, so the struct must not be modified concurrently from other goroutines.
+// It panics if x is not a non-nil pointer to a struct.
+func ExposeLive(property string, x interface{}) {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic("cannot expose " + reflect.TypeOf(x).String() + " as it is not a non-nil pointer to a struct")
+	}
+
+	bridge.Set(property, newLiveStruct(v.Elem()).proxy)
+}
+
+// liveStruct binds the properties of a JS Proxy to the fields of an addressable struct.
+type liveStruct struct {
+	v     reflect.Value
+	proxy js.Value
+
+	se structEncoder
+	sd structDecoder
+
+	// Indexes of the fields, methods and accessors by the name of their property.
+	fields    map[string]int
+	methods   map[string]structMethod
+	accessors map[string]structAccessor
+
+	// JS functions of the methods and Proxies of the nested structs by field index, created when first read.
+	funcs    map[string]js.Value
+	children map[int]*liveStruct
+}
+
+// newLiveStruct creates the Proxy bound to the provided addressable struct.
+func newLiveStruct(v reflect.Value) *liveStruct {
+	l := &liveStruct{
+		v:         v,
+		se:        compileStructEncoder(v.Type()),
+		sd:        compileStructDecoder(v.Type()),
+		fields:    map[string]int{},
+		methods:   map[string]structMethod{},
+		accessors: map[string]structAccessor{},
+		funcs:     map[string]js.Value{},
+		children:  map[int]*liveStruct{},
+	}
+	for i, f := range l.se.fields {
+		l.fields[f.name] = i
+	}
+	for _, m := range l.se.methods {
+		l.methods[m.name] = m
+	}
+	for _, a := range l.se.accessors {
+		l.accessors[a.name] = a
+	}
+
+	handler := globalConstructor("Object").New()
+	handler.Set("get", throwingFunc(l.get).JSValue())
+	handler.Set("set", throwingFunc(l.set).JSValue())
+	handler.Set("has", throwingFunc(l.has).JSValue())
+	handler.Set("ownKeys", throwingFunc(l.ownKeys).JSValue())
+	handler.Set("getOwnPropertyDescriptor", throwingFunc(l.getOwnPropertyDescriptor).JSValue())
+
+	l.proxy = globalConstructor("Proxy").New(globalConstructor("Object").New(), handler)
+	return l
+}
+
+// get implements the get trap of the Proxy, with the target and the property as arguments.
+func (l *liveStruct) get(this js.Value, args []js.Value) (js.Value, error) {
+	name, ok := propertyName(args[1])
+	if !ok {
+		return js.Undefined(), nil
+	}
+
+	if i, ok := l.fields[name]; ok {
+		return l.read(i)
+	}
+	if a, ok := l.accessors[name]; ok {
+		return l.callGetter(a)
+	}
+	if m, ok := l.methods[name]; ok {
+		fn, ok := l.funcs[name]
+		if !ok {
+			method, _ := m.method(l.v)
+			fn = toJSFunc(method, defaultEncoder.opts).JSValue()
+			l.funcs[name] = fn
+		}
+		return fn, nil
+	}
+	return js.Undefined(), nil
+}
+
+// set implements the set trap of the Proxy, with the target, the property and the value as arguments.
+func (l *liveStruct) set(this js.Value, args []js.Value) (js.Value, error) {
+	name, ok := propertyName(args[1])
+	if !ok {
+		return js.Undefined(), errors.New("cannot set a symbol-keyed property on " + l.v.Type().String())
+	}
+
+	if i, ok := l.fields[name]; ok {
+		d := &decodeState{opts: defaultDecoder.opts}
+		if err := l.sd.decodeField(d, i, args[2], l.v); err != nil {
+			return js.Undefined(), withProperty(err, name, args[2], l.sd.fields[i].typ)
+		}
+		return js.ValueOf(true), nil
+	}
+	if a, ok := l.accessors[name]; ok && a.set != nil {
+		return js.ValueOf(true), l.callSetter(a, args[2])
+	}
+	return js.Undefined(), errors.New("cannot set property " + name + " as it is not a field of " + l.v.Type().String())
+}
+
+// has implements the has trap of the Proxy, with the target and the property as arguments.
+func (l *liveStruct) has(this js.Value, args []js.Value) (js.Value, error) {
+	name, ok := propertyName(args[1])
+	if !ok {
+		return js.ValueOf(false), nil
+	}
+
+	_, isField := l.fields[name]
+	_, isAccessor := l.accessors[name]
+	_, isMethod := l.methods[name]
+	return js.ValueOf(isField || isAccessor || isMethod), nil
+}
+
+// ownKeys implements the ownKeys trap of the Proxy, listing the fields and accessors.
+func (l *liveStruct) ownKeys(this js.Value, args []js.Value) (js.Value, error) {
+	keys := make([]interface{}, 0, len(l.se.fields)+len(l.se.accessors))
+	for _, f := range l.se.fields {
+		keys = append(keys, f.name)
+	}
+	for _, a := range l.se.accessors {
+		keys = append(keys, a.name)
+	}
+	return js.ValueOf(keys), nil
+}
+
+// getOwnPropertyDescriptor implements the getOwnPropertyDescriptor trap of the Proxy, with the target and the
+// property as arguments. Fields and accessors are described as enumerable data properties holding their value.
+func (l *liveStruct) getOwnPropertyDescriptor(this js.Value, args []js.Value) (js.Value, error) {
+	name, ok := propertyName(args[1])
+	if !ok {
+		return js.Undefined(), nil
+	}
+
+	_, isField := l.fields[name]
+	_, isAccessor := l.accessors[name]
+	if !isField && !isAccessor {
+		return js.Undefined(), nil
+	}
+
+	value, err := l.get(this, args)
+	if err != nil {
+		return js.Undefined(), err
+	}
+
+	descriptor := globalConstructor("Object").New()
+	descriptor.Set("value", value)
+	descriptor.Set("writable", true)
+	descriptor.Set("enumerable", true)
+	descriptor.Set("configurable", true)
+	return descriptor, nil
+}
+
+// read converts the current value of the i-th field to JS.
+// Nested structs are returned as Proxies bound to them.
+func (l *liveStruct) read(i int) (result js.Value, err error) {
+	fv, _ := fieldByIndex(l.v, l.se.fields[i].index, false)
+	if !fv.IsValid() {
+		// The field is promoted from a nil embedded pointer.
+		return js.Undefined(), nil
+	}
+
+	if child, ok := l.child(i, fv); ok {
+		return child.proxy, nil
+	}
+
+	defer recoverCycle(&err)
+	e := &encodeState{opts: defaultEncoder.opts}
+	return l.se.encodeField(e, i, fv), nil
+}
+
+// child returns the liveStruct bound to the i-th field, fv, if it holds a struct, or a non-nil pointer to one, that
+// is converted to a plain JS object. The same Proxy is returned as long as the field refers to the same struct, and
+// it is replaced once the field is reassigned from Go.
+func (l *liveStruct) child(i int, fv reflect.Value) (*liveStruct, bool) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			delete(l.children, i)
+			return nil, false
+		}
+		fv = fv.Elem()
+	}
+	t := fv.Type()
+	if t.Kind() != reflect.Struct || t == jsValueType || hasCustomEncoding(t) {
+		return nil, false
+	}
+
+	child, ok := l.children[i]
+	if !ok || child.v.Addr().Pointer() != fv.Addr().Pointer() {
+		child = newLiveStruct(fv)
+		l.children[i] = child
+	}
+	return child, true
+}
+
+// callGetter calls the getter of the provided accessor and converts its result to JS.
+func (l *liveStruct) callGetter(a structAccessor) (result js.Value, err error) {
+	if a.get == nil {
+		return js.Undefined(), nil
+	}
+
+	fn, _ := a.get.method(l.v)
+	out := fn.Call(nil)
+	if len(out) == 2 && fn.Type().Out(1) == errorType && !out[1].IsNil() {
+		return js.Undefined(), out[1].Interface().(error)
+	}
+	if len(out) == 0 {
+		return js.Undefined(), nil
+	}
+	return defaultEncoder.Encode(out[0].Interface())
+}
+
+// callSetter converts the provided JS value to the parameter of the setter of the accessor and calls it.
+func (l *liveStruct) callSetter(a structAccessor, x js.Value) error {
+	fn, _ := a.set.method(l.v)
+	arg := reflect.New(fn.Type().In(0))
+	if err := defaultDecoder.FromJSValue(x, arg.Interface()); err != nil {
+		return err
+	}
+
+	out := fn.Call([]reflect.Value{arg.Elem()})
+	if len(out) != 0 && fn.Type().Out(len(out)-1) == errorType && !out[len(out)-1].IsNil() {
+		return out[len(out)-1].Interface().(error)
+	}
+	return nil
+}
+
+// propertyName returns the name of the property passed to a Proxy trap, or false if it is a symbol.
+func propertyName(x js.Value) (string, bool) {
+	if x.Type() != js.TypeString {
+		return "", false
+	}
+	return x.String(), true
+}
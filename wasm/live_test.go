@@ -0,0 +1,53 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExposeLive(t *testing.T) {
+	type inner struct {
+		X int
+	}
+	type state struct {
+		Items []int
+		Inner *inner
+	}
+
+	s := &state{Items: []int{1}, Inner: &inner{X: 1}}
+	ExposeLive("liveState", s)
+	defer bridge.Delete("liveState")
+	proxy := bridge.value.Get("liveState")
+
+	t.Run("slices are copies", func(t *testing.T) {
+		proxy.Get("Items").Call("push", 2)
+		if !reflect.DeepEqual(s.Items, []int{1}) {
+			t.Errorf("push changed Items to %v", s.Items)
+		}
+
+		proxy.Set("Items", []interface{}{1, 2})
+		if !reflect.DeepEqual(s.Items, []int{1, 2}) {
+			t.Errorf("assigning Items set it to %v", s.Items)
+		}
+	})
+
+	t.Run("nested structs", func(t *testing.T) {
+		first := proxy.Get("Inner")
+		if !first.Equal(proxy.Get("Inner")) {
+			t.Error("reading Inner twice returned different Proxies")
+		}
+		first.Set("X", 2)
+		if s.Inner.X != 2 {
+			t.Errorf("setting Inner.X set it to %d", s.Inner.X)
+		}
+
+		s.Inner = &inner{X: 3}
+		second := proxy.Get("Inner")
+		if second.Equal(first) {
+			t.Error("reading Inner after reassigning it returned the stale Proxy")
+		}
+		if x := second.Get("X").Int(); x != 3 {
+			t.Errorf("Inner.X = %d after reassigning Inner, want 3", x)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package wasm
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"syscall/js"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// hasCustomEncoding checks if the provided type, or a pointer to it, encodes or decodes itself through Wrapper,
+// Decoder or the marshaling interfaces of encoding and encoding/json.
+func hasCustomEncoding(t reflect.Type) bool {
+	customTypes := []reflect.Type{
+		wrapperType, decoderType,
+		textMarshalerType, textUnmarshalerType,
+		jsonMarshalerType, jsonUnmarshalerType,
+	}
+	for _, m := range customTypes {
+		if t.Implements(m) || reflect.PtrTo(t).Implements(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMarshalerEncoder returns the encoderFunc of a type implementing encoding.TextMarshaler or json.Marshaler.
+// If only a pointer to the type implements them, they are used when the value is addressable and fallback is used
+// otherwise. It returns nil if the type does not implement either interface.
+func newMarshalerEncoder(t reflect.Type, fallback func() encoderFunc) encoderFunc {
+	switch {
+	case t.Implements(textMarshalerType):
+		return textMarshalerEncoder
+	case t.Implements(jsonMarshalerType):
+		return jsonMarshalerEncoder
+	case t.Kind() == reflect.Ptr:
+		return nil
+	case reflect.PtrTo(t).Implements(textMarshalerType):
+		return newCondAddrEncoder(addrEncoder(textMarshalerEncoder), fallback())
+	case reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return newCondAddrEncoder(addrEncoder(jsonMarshalerEncoder), fallback())
+	default:
+		return nil
+	}
+}
+
+// textMarshalerEncoder converts a value implementing encoding.TextMarshaler to a JS string.
+func textMarshalerEncoder(e *encodeState, v reflect.Value) js.Value {
+	switch {
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		return js.Undefined()
+	case v.Kind() == reflect.Interface && v.IsNil():
+		// Interface types implementing the interface use this encoder as well.
+		return js.Null()
+	}
+
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling %s to text: %v", v.Type(), err))
+	}
+	return js.ValueOf(string(text))
+}
+
+// jsonMarshalerEncoder converts a value implementing json.Marshaler to the JS value obtained from JSON.parse.
+func jsonMarshalerEncoder(e *encodeState, v reflect.Value) js.Value {
+	switch {
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		return js.Undefined()
+	case v.Kind() == reflect.Interface && v.IsNil():
+		// Interface types implementing the interface use this encoder as well.
+		return js.Null()
+	}
+
+	text, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		panic(fmt.Sprintf("error marshalling %s to JSON: %v", v.Type(), err))
+	}
+
+	parse, err := Global().Expect(js.TypeFunction, "JSON", "parse")
+	if err != nil {
+		panic("JSON.parse not found")
+	}
+	return parse.Invoke(string(text))
+}
+
+// addrEncoder returns an encoderFunc that calls enc with the address of the value.
+func addrEncoder(enc encoderFunc) encoderFunc {
+	return func(e *encodeState, v reflect.Value) js.Value {
+		return enc(e, v.Addr())
+	}
+}
+
+// newCondAddrEncoder returns an encoderFunc that uses canAddrEnc if the value is addressable and elseEnc otherwise.
+func newCondAddrEncoder(canAddrEnc, elseEnc encoderFunc) encoderFunc {
+	return func(e *encodeState, v reflect.Value) js.Value {
+		if v.CanAddr() {
+			return canAddrEnc(e, v)
+		}
+		return elseEnc(e, v)
+	}
+}
+
+// newUnmarshalerDecoder returns the decoderFunc of a type whose pointer implements encoding.TextUnmarshaler or
+// json.Unmarshaler. JS strings are decoded with UnmarshalText when available. Other values are decoded with
+// UnmarshalJSON, or with fallback if json.Unmarshaler is not implemented.
+// It returns nil if the type does not implement either interface.
+func newUnmarshalerDecoder(t reflect.Type, fallback func() decoderFunc) decoderFunc {
+	ptr := reflect.PtrTo(t)
+	isText := ptr.Implements(textUnmarshalerType)
+	isJSON := ptr.Implements(jsonUnmarshalerType)
+	if !isText && !isJSON {
+		return nil
+	}
+
+	otherDec := decodeWithJSONUnmarshaler
+	if !isJSON {
+		otherDec = fallback()
+	}
+	if !isText {
+		return otherDec
+	}
+
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if x.Type() == js.TypeString {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(x.String()))
+		}
+		return otherDec(d, x, v)
+	}
+}
+
+// decodeWithJSONUnmarshaler passes the JSON.stringify representation of the js.Value to the json.Unmarshaler
+// implementation of the provided reflect.Value.
+func decodeWithJSONUnmarshaler(d *decodeState, x js.Value, v reflect.Value) error {
+	stringify, err := Global().Expect(js.TypeFunction, "JSON", "stringify")
+	if err != nil {
+		panic("JSON.stringify not found")
+	}
+
+	text := stringify.Invoke(x)
+	if text.Type() != js.TypeString {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+	return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON([]byte(text.String()))
+}
@@ -0,0 +1,42 @@
+package wasm
+
+import (
+	"encoding"
+	"encoding/json"
+	"net"
+	"syscall/js"
+	"testing"
+)
+
+func TestEncodeMarshalers(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want js.Value
+	}{
+		{"text", struct{ IP net.IP }{net.IPv4(127, 0, 0, 1)}, js.ValueOf("127.0.0.1")},
+		{"nil text interface", struct{ M encoding.TextMarshaler }{}, js.Null()},
+		{"nil JSON interface", struct{ M json.Marshaler }{}, js.Null()},
+		{"text interface", struct{ M encoding.TextMarshaler }{net.IPv4(127, 0, 0, 1)}, js.ValueOf("127.0.0.1")},
+		{"JSON interface", struct{ M json.Marshaler }{json.RawMessage(`"raw"`)}, js.ValueOf("raw")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := defaultEncoder.Encode(tt.in)
+			if err != nil {
+				t.Fatalf("Encode(%#v) returned error: %v", tt.in, err)
+			}
+
+			var got js.Value
+			if f := x.Get("IP"); !f.IsUndefined() {
+				got = f
+			} else {
+				got = x.Get("M")
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Encode(%#v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
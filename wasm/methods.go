@@ -0,0 +1,251 @@
+package wasm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall/js"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MethodOptions controls which exported methods of a struct are converted to JS, and how.
+type MethodOptions struct {
+	// Include lists the Go names of the methods to convert. If it is empty, every exported method is converted.
+	Include []string
+	// Exclude lists the Go names of the methods not to convert, such as String or Error.
+	Exclude []string
+
+	// Accessors converts a GetX method, a SetX method, or both, into the accessor property X instead of functions.
+	// The name of the property follows the NamingPolicy of the struct.
+	Accessors bool
+	// Properties declares accessor properties explicitly, by the name of their JS property.
+	Properties map[string]Accessor
+}
+
+// Accessor names the methods backing a JS accessor property.
+// Get must take no argument and Set must take exactly one. Either can be empty for a write-only or read-only property.
+type Accessor struct {
+	Get string
+	Set string
+}
+
+var (
+	methodsMu      sync.RWMutex
+	defaultMethods MethodOptions
+
+	// typeMethodOptions holds the MethodOptions set for specific struct types.
+	typeMethodOptions = map[reflect.Type]MethodOptions{}
+)
+
+// SetMethodOptions sets the MethodOptions of every struct type without options of its own.
+// It should be called before any value is converted, such as in an init function.
+func SetMethodOptions(opts MethodOptions) {
+	methodsMu.Lock()
+	defaultMethods = opts
+	methodsMu.Unlock()
+
+	resetTypeCaches()
+}
+
+// SetTypeMethodOptions sets the MethodOptions of the struct type of the provided value, or of the struct it points to.
+// It should be called before any value of the type is converted, such as in an init function.
+func SetTypeMethodOptions(x interface{}, opts MethodOptions) {
+	t := reflect.TypeOf(x)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic("cannot set the method options of " + t.String() + " as it is not a struct")
+	}
+
+	methodsMu.Lock()
+	typeMethodOptions[t] = opts
+	methodsMu.Unlock()
+
+	resetTypeCaches()
+}
+
+// methodOptions returns the MethodOptions of the provided struct type.
+func methodOptions(t reflect.Type) MethodOptions {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+
+	if opts, ok := typeMethodOptions[t]; ok {
+		return opts
+	}
+	return defaultMethods
+}
+
+// allows checks if the method with the provided Go name can be converted.
+func (opts MethodOptions) allows(goName string) bool {
+	if len(opts.Include) != 0 && !containsString(opts.Include, goName) {
+		return false
+	}
+	return !containsString(opts.Exclude, goName)
+}
+
+// structMethod is an exported method of a struct converted to JS.
+type structMethod struct {
+	name string
+
+	// Index of the method in the method set of the struct, or -1 if it has a pointer receiver.
+	valueIndex int
+	// Index of the method in the method set of the pointer to the struct.
+	ptrIndex int
+}
+
+// structAccessor is a JS accessor property backed by methods of a struct.
+type structAccessor struct {
+	name     string
+	get, set *structMethod
+}
+
+// typeMethods returns the methods of the provided struct type that are converted to JS functions and the accessor
+// properties backed by its methods, according to its MethodOptions.
+func typeMethods(t reflect.Type) ([]structMethod, []structAccessor) {
+	opts := methodOptions(t)
+	naming := namingPolicy(t)
+	ptr := reflect.PtrTo(t)
+
+	lookup := func(goName string, numIn int) *structMethod {
+		m, ok := ptr.MethodByName(goName)
+		// The receiver is the first parameter of the method type.
+		if !ok || !opts.allows(goName) || m.Type.NumIn() != numIn+1 {
+			return nil
+		}
+
+		sm := &structMethod{name: naming.name(goName), valueIndex: -1, ptrIndex: m.Index}
+		if vm, ok := t.MethodByName(goName); ok {
+			sm.valueIndex = vm.Index
+		}
+		return sm
+	}
+
+	// Go names of the methods backing an accessor, which are not converted to functions.
+	used := map[string]bool{}
+	var accessors []structAccessor
+	addAccessor := func(name string, a Accessor) {
+		sa := structAccessor{
+			name: name,
+			get:  lookup(a.Get, 0),
+			set:  lookup(a.Set, 1),
+		}
+		if sa.get == nil && sa.set == nil {
+			return
+		}
+		if sa.get != nil {
+			used[a.Get] = true
+		}
+		if sa.set != nil {
+			used[a.Set] = true
+		}
+		accessors = append(accessors, sa)
+	}
+
+	names := make([]string, 0, len(opts.Properties))
+	for name := range opts.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addAccessor(name, opts.Properties[name])
+	}
+
+	if opts.Accessors {
+		for i := 0; i < ptr.NumMethod(); i++ {
+			goName := ptr.Method(i).Name
+			if used[goName] {
+				continue
+			}
+
+			if property, ok := trimAccessorPrefix(goName, "Get"); ok {
+				addAccessor(naming.name(property), Accessor{Get: goName, Set: "Set" + property})
+			} else if property, ok := trimAccessorPrefix(goName, "Set"); ok {
+				if _, hasGetter := ptr.MethodByName("Get" + property); !hasGetter {
+					addAccessor(naming.name(property), Accessor{Set: goName})
+				}
+			}
+		}
+	}
+
+	var methods []structMethod
+	for i := 0; i < ptr.NumMethod(); i++ {
+		goName := ptr.Method(i).Name
+		if used[goName] || !opts.allows(goName) {
+			continue
+		}
+
+		sm := structMethod{name: naming.name(goName), valueIndex: -1, ptrIndex: i}
+		if vm, ok := t.MethodByName(goName); ok {
+			sm.valueIndex = vm.Index
+		}
+		methods = append(methods, sm)
+	}
+
+	return methods, accessors
+}
+
+// trimAccessorPrefix returns the name of the property of a GetX or SetX method.
+// The prefix must be followed by an upper case letter, so that a method such as Settle is left alone.
+func trimAccessorPrefix(goName, prefix string) (string, bool) {
+	if !strings.HasPrefix(goName, prefix) {
+		return "", false
+	}
+
+	property := goName[len(prefix):]
+	r, _ := utf8.DecodeRuneInString(property)
+	if !unicode.IsUpper(r) {
+		return "", false
+	}
+	return property, true
+}
+
+// method returns the method of the provided struct, bound to a pointer to it if it is addressable.
+// It returns false if the method has a pointer receiver and the struct is not addressable.
+func (m structMethod) method(x reflect.Value) (reflect.Value, bool) {
+	if x.CanAddr() {
+		return x.Addr().Method(m.ptrIndex), true
+	}
+	if m.valueIndex < 0 {
+		return reflect.Value{}, false
+	}
+	return x.Method(m.valueIndex), true
+}
+
+// define defines the accessor property on the provided JS object, calling the methods of the provided struct.
+// Methods with a pointer receiver are left out if the struct is not addressable.
+func (a structAccessor) define(e *encodeState, obj js.Value, x reflect.Value) {
+	descriptor := globalConstructor("Object").New()
+	descriptor.Set("enumerable", true)
+	descriptor.Set("configurable", true)
+
+	var defined bool
+	if a.get != nil {
+		if fn, ok := a.get.method(x); ok {
+			descriptor.Set("get", toJSFunc(fn, e.opts).JSValue())
+			defined = true
+		}
+	}
+	if a.set != nil {
+		if fn, ok := a.set.method(x); ok {
+			descriptor.Set("set", toJSFunc(fn, e.opts).JSValue())
+			defined = true
+		}
+	}
+
+	if defined {
+		globalConstructor("Object").Call("defineProperty", obj, a.name, descriptor)
+	}
+}
+
+// containsString checks if the provided slice contains the string.
+func containsString(s []string, x string) bool {
+	for _, v := range s {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
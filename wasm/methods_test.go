@@ -0,0 +1,162 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+type methodsCounter struct {
+	count int
+	label string
+}
+
+func (c *methodsCounter) Get() int          { return c.count }
+func (c *methodsCounter) GetCount() int     { return c.count }
+func (c *methodsCounter) SetCount(n int)    { c.count = n }
+func (c *methodsCounter) GetTotal() int     { return c.count }
+func (c *methodsCounter) SetLabel(s string) { c.label = s }
+func (c *methodsCounter) Settle()           {}
+func (c *methodsCounter) Reset()            { c.count = 0 }
+func (c methodsCounter) Size() int          { return c.count }
+
+// methodsCounter types with their own MethodOptions, as options are set per type.
+type (
+	methodsDefault    struct{ methodsCounter }
+	methodsInclude    struct{ methodsCounter }
+	methodsExclude    struct{ methodsCounter }
+	methodsAccessors  struct{ methodsCounter }
+	methodsIncluded   struct{ methodsCounter }
+	methodsProperties struct{ methodsCounter }
+	methodsBoth       struct{ methodsCounter }
+)
+
+func TestTypeMethods(t *testing.T) {
+	// accessor describes a structAccessor by its name and which of its methods are set.
+	type accessor struct {
+		name     string
+		get, set bool
+	}
+
+	tests := []struct {
+		name          string
+		x             interface{}
+		opts          MethodOptions
+		wantMethods   []string
+		wantAccessors []accessor
+	}{
+		{
+			name:        "all",
+			x:           methodsDefault{},
+			wantMethods: []string{"Get", "GetCount", "GetTotal", "Reset", "SetCount", "SetLabel", "Settle", "Size"},
+		},
+		{
+			name:        "include",
+			x:           methodsInclude{},
+			opts:        MethodOptions{Include: []string{"Reset", "Size"}},
+			wantMethods: []string{"Reset", "Size"},
+		},
+		{
+			name:        "exclude",
+			x:           methodsExclude{},
+			opts:        MethodOptions{Exclude: []string{"Reset", "Settle"}},
+			wantMethods: []string{"Get", "GetCount", "GetTotal", "SetCount", "SetLabel", "Size"},
+		},
+		{
+			name:          "accessors",
+			x:             methodsAccessors{},
+			opts:          MethodOptions{Accessors: true},
+			wantMethods:   []string{"Get", "Reset", "Settle", "Size"},
+			wantAccessors: []accessor{{"Count", true, true}, {"Total", true, false}, {"Label", false, true}},
+		},
+		{
+			name:          "accessors with include",
+			x:             methodsIncluded{},
+			opts:          MethodOptions{Include: []string{"GetCount", "Reset"}, Accessors: true},
+			wantMethods:   []string{"Reset"},
+			wantAccessors: []accessor{{"Count", true, false}},
+		},
+		{
+			name: "properties",
+			x:    methodsProperties{},
+			opts: MethodOptions{Properties: map[string]Accessor{
+				"amount":  {Get: "GetCount", Set: "SetCount"},
+				"missing": {Get: "Missing"},
+			}},
+			wantMethods:   []string{"Get", "GetTotal", "Reset", "SetLabel", "Settle", "Size"},
+			wantAccessors: []accessor{{"amount", true, true}},
+		},
+		{
+			name: "properties with accessors",
+			x:    methodsBoth{},
+			opts: MethodOptions{Accessors: true, Properties: map[string]Accessor{"amount": {Get: "GetCount"}}},
+			// SetCount is left as a method, as its getter already backs another property.
+			wantMethods:   []string{"Get", "Reset", "SetCount", "Settle", "Size"},
+			wantAccessors: []accessor{{"amount", true, false}, {"Total", true, false}, {"Label", false, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTypeMethodOptions(tt.x, tt.opts)
+			methods, accessors := typeMethods(reflect.TypeOf(tt.x))
+
+			var gotMethods []string
+			for _, m := range methods {
+				gotMethods = append(gotMethods, m.name)
+			}
+			if !reflect.DeepEqual(gotMethods, tt.wantMethods) {
+				t.Errorf("methods = %q, want %q", gotMethods, tt.wantMethods)
+			}
+
+			var gotAccessors []accessor
+			for _, a := range accessors {
+				gotAccessors = append(gotAccessors, accessor{a.name, a.get != nil, a.set != nil})
+			}
+			if !reflect.DeepEqual(gotAccessors, tt.wantAccessors) {
+				t.Errorf("accessors = %+v, want %+v", gotAccessors, tt.wantAccessors)
+			}
+		})
+	}
+}
+
+func TestTrimAccessorPrefix(t *testing.T) {
+	tests := []struct {
+		goName, prefix string
+		want           string
+		wantOK         bool
+	}{
+		{"GetCount", "Get", "Count", true},
+		{"SetCount", "Set", "Count", true},
+		{"GetURL", "Get", "URL", true},
+		{"Settle", "Set", "", false},
+		{"Getaway", "Get", "", false},
+		{"Get", "Get", "", false},
+		{"Count", "Get", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := trimAccessorPrefix(tt.goName, tt.prefix)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("trimAccessorPrefix(%q, %q) = %q, %t, want %q, %t", tt.goName, tt.prefix, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestEncodeAccessors(t *testing.T) {
+	type counter struct{ methodsCounter }
+	SetTypeMethodOptions(counter{}, MethodOptions{Accessors: true})
+
+	c := &counter{}
+	x := ToJSValue(c)
+	x.Set("Count", 4)
+	if c.count != 4 {
+		t.Errorf("setting Count from JS set the count to %d, want 4", c.count)
+	}
+	if got := x.Get("Total").Int(); got != 4 {
+		t.Errorf("Total = %d, want 4", got)
+	}
+	x.Call("Reset")
+	if got := x.Get("Count").Int(); got != 0 {
+		t.Errorf("Count after Reset = %d, want 0", got)
+	}
+}
@@ -0,0 +1,131 @@
+package wasm
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NamingPolicy decides the name of the JS property of an exported struct field or method without a wasm tag name.
+type NamingPolicy int
+
+const (
+	// Verbatim keeps the Go name as is, such as UserID. It is the default policy.
+	Verbatim NamingPolicy = iota
+	// LowerCamelCase converts the Go name to lower camel case, such as userId.
+	LowerCamelCase
+	// SnakeCase converts the Go name to snake case, such as user_id.
+	SnakeCase
+)
+
+var (
+	namingMu      sync.RWMutex
+	defaultNaming NamingPolicy
+
+	// typeNamings holds the NamingPolicy set for specific struct types.
+	typeNamings = map[reflect.Type]NamingPolicy{}
+)
+
+// SetNamingPolicy sets the NamingPolicy of every struct type without a policy of its own.
+// It should be called before any value is converted, such as in an init function.
+func SetNamingPolicy(p NamingPolicy) {
+	namingMu.Lock()
+	defaultNaming = p
+	namingMu.Unlock()
+
+	resetTypeCaches()
+}
+
+// SetTypeNamingPolicy sets the NamingPolicy of the struct type of the provided value, or of the struct it points to.
+// It applies to the fields promoted from embedded structs as well.
+// It should be called before any value of the type is converted, such as in an init function.
+func SetTypeNamingPolicy(x interface{}, p NamingPolicy) {
+	t := reflect.TypeOf(x)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic("cannot set the naming policy of " + t.String() + " as it is not a struct")
+	}
+
+	namingMu.Lock()
+	typeNamings[t] = p
+	namingMu.Unlock()
+
+	resetTypeCaches()
+}
+
+// namingPolicy returns the NamingPolicy of the provided struct type.
+func namingPolicy(t reflect.Type) NamingPolicy {
+	namingMu.RLock()
+	defer namingMu.RUnlock()
+
+	if p, ok := typeNamings[t]; ok {
+		return p
+	}
+	return defaultNaming
+}
+
+// resetTypeCaches drops the compiled encoders and decoders, whose property names depend on the naming policies.
+func resetTypeCaches() {
+	for _, cache := range []*sync.Map{&encoderCache, &decoderCache} {
+		cache.Range(func(key, value interface{}) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
+
+// name converts the provided Go name according to the policy.
+func (p NamingPolicy) name(goName string) string {
+	switch p {
+	case LowerCamelCase:
+		words := splitWords(goName)
+		for i, w := range words {
+			r := []rune(strings.ToLower(w))
+			if i > 0 {
+				r[0] = unicode.ToUpper(r[0])
+			}
+			words[i] = string(r)
+		}
+		return strings.Join(words, "")
+	case SnakeCase:
+		words := splitWords(goName)
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+		return strings.Join(words, "_")
+	default:
+		return goName
+	}
+}
+
+// splitWords splits a Go name into its words, keeping initialisms together: UserID is split into User and ID, and
+// HTTPServer into HTTP and Server. Underscores separate words as well.
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var words []string
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		boundary := i == len(runes) || runes[i] == '_'
+		if !boundary && i > start && unicode.IsUpper(runes[i]) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			boundary = !unicode.IsUpper(prev) || nextIsLower
+		}
+		if !boundary {
+			continue
+		}
+
+		if i > start {
+			words = append(words, string(runes[start:i]))
+		}
+		start = i
+		if i < len(runes) && runes[i] == '_' {
+			start++
+		}
+	}
+	return words
+}
@@ -0,0 +1,116 @@
+package wasm
+
+import (
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Name", []string{"Name"}},
+		{"UserID", []string{"User", "ID"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"ServeHTTP", []string{"Serve", "HTTP"}},
+		{"ID", []string{"ID"}},
+		{"Base64Data", []string{"Base64", "Data"}},
+		{"user_id", []string{"user", "id"}},
+		{"A", []string{"A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := splitWords(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitWords(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamingPolicyName(t *testing.T) {
+	tests := []struct {
+		policy NamingPolicy
+		in     string
+		want   string
+	}{
+		{Verbatim, "UserID", "UserID"},
+		{LowerCamelCase, "UserID", "userId"},
+		{LowerCamelCase, "HTTPServer", "httpServer"},
+		{SnakeCase, "UserID", "user_id"},
+		{SnakeCase, "HTTPServer", "http_server"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.name(tt.in); got != tt.want {
+			t.Errorf("NamingPolicy(%d).name(%q) = %q, want %q", tt.policy, tt.in, got, tt.want)
+		}
+	}
+}
+
+type namingBase struct {
+	UserID int
+}
+
+type namingUser struct {
+	namingBase
+	DisplayName string
+	HTTPProxy   string `wasm:"HTTPProxy"`
+}
+
+func (u namingUser) FullName() string {
+	return u.DisplayName
+}
+
+type namingDefault struct {
+	UserID int
+}
+
+func TestTypeNamingPolicy(t *testing.T) {
+	SetTypeNamingPolicy(namingUser{}, SnakeCase)
+
+	x := ToJSValue(namingUser{namingBase{1}, "Ada", "proxy"})
+	for _, name := range []string{"user_id", "display_name", "HTTPProxy"} {
+		if x.Get(name).IsUndefined() {
+			t.Errorf("ToJSValue did not set the %q property", name)
+		}
+	}
+	if fn := x.Get("full_name"); fn.Type() != js.TypeFunction || fn.Invoke().String() != "Ada" {
+		t.Errorf("ToJSValue did not name the FullName method full_name, got %v", fn)
+	}
+
+	var got namingUser
+	if err := FromJSValue(evalJS(`{user_id: 2, display_name: "Grace", HTTPProxy: "p"}`), &got); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	if want := (namingUser{namingBase{2}, "Grace", "p"}); got != want {
+		t.Errorf("FromJSValue = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetNamingPolicy(t *testing.T) {
+	if x := ToJSValue(namingDefault{1}); x.Get("UserID").IsUndefined() {
+		t.Fatal("ToJSValue did not use the Verbatim policy by default")
+	}
+	var before namingDefault
+	if err := FromJSValue(evalJS(`{UserID: 1}`), &before); err != nil || before.UserID != 1 {
+		t.Fatalf("FromJSValue did not use the Verbatim policy by default, got %+v, %v", before, err)
+	}
+
+	SetNamingPolicy(LowerCamelCase)
+	defer SetNamingPolicy(Verbatim)
+
+	if x := ToJSValue(namingDefault{1}); x.Get("userId").IsUndefined() || !x.Get("UserID").IsUndefined() {
+		t.Error("ToJSValue kept the property names compiled before SetNamingPolicy")
+	}
+
+	var got namingDefault
+	if err := FromJSValue(evalJS(`{userId: 3}`), &got); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	if got.UserID != 3 {
+		t.Errorf("FromJSValue kept the property names compiled before SetNamingPolicy, got %+v", got)
+	}
+}
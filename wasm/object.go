@@ -81,6 +81,18 @@ func (o Object) Expect(expectedType js.Type, path ...string) (js.Value, error) {
 	return value, nil
 }
 
+// GetSymbol returns the property of the object keyed by the provided symbol.
+// It is implemented by calling Reflect.get on JS.
+func (o Object) GetSymbol(s Symbol) js.Value {
+	return getSymbolProperty(o.value, s.value)
+}
+
+// SetSymbol sets the property keyed by the provided symbol to the value of ToJSValue(x).
+// It is implemented by calling Reflect.set on JS. Like ToJSValue, it panics with a CycleError if x refers back to itself.
+func (o Object) SetSymbol(s Symbol, x interface{}) {
+	setSymbolProperty(o.value, s.value, ToJSValue(x))
+}
+
 // Delete removes property p from the object.
 func (o Object) Delete(p string) {
 	o.value.Delete(p)
@@ -117,11 +129,13 @@ func (o Object) Length() int {
 }
 
 // Set sets the property p to the value of ToJSValue(x).
+// Like ToJSValue, it panics with a CycleError if x refers back to itself.
 func (o Object) Set(p string, x interface{}) {
 	o.value.Set(p, ToJSValue(x))
 }
 
 // SetIndex sets the index i to the value of ToJSValue(x).
+// Like ToJSValue, it panics with a CycleError if x refers back to itself.
 func (o Object) SetIndex(i int, x interface{}) {
 	o.value.SetIndex(i, ToJSValue(x))
 }
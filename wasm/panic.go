@@ -0,0 +1,76 @@
+package wasm
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is a panic recovered from a Go function called by JS or from the handler of NewPromise.
+// It is thrown in JS as an Error named GoPanic, with the Go stack trace in its goStack property.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string
+}
+
+// Error implements error.
+func (e PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error, such as a runtime.Error.
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// JSName implements ErrorNamer.
+func (e PanicError) JSName() string {
+	return "GoPanic"
+}
+
+// JSProps implements ErrorPropsProvider.
+func (e PanicError) JSProps() map[string]interface{} {
+	return map[string]interface{}{
+		"goStack": e.Stack,
+	}
+}
+
+var (
+	panicHandlerMu sync.RWMutex
+	panicHandler   func(PanicError)
+)
+
+// SetPanicHandler sets a function that is called with every panic recovered from a Go function called by JS or from
+// the handler of NewPromise, such as to report it. The handler is called in its own goroutine, while the panic is
+// thrown or rejected in JS. Panics in other goroutines started by the program are not recovered.
+// A nil handler removes the previous one.
+func SetPanicHandler(handler func(PanicError)) {
+	panicHandlerMu.Lock()
+	panicHandler = handler
+	panicHandlerMu.Unlock()
+}
+
+// recoverPanic recovers a panic and stores it as a PanicError in err, reporting it to the panic handler.
+// It must be deferred directly.
+func recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	p := PanicError{
+		Value: r,
+		Stack: string(debug.Stack()),
+	}
+	*err = p
+
+	panicHandlerMu.RLock()
+	handler := panicHandler
+	panicHandlerMu.RUnlock()
+	if handler != nil {
+		go handler(p)
+	}
+}
@@ -0,0 +1,91 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExposedFuncPanic(t *testing.T) {
+	handled := make(chan PanicError, 2)
+	SetPanicHandler(func(p PanicError) {
+		handled <- p
+	})
+	defer SetPanicHandler(nil)
+
+	Expose("panicSync", func() int {
+		panic("boom")
+	})
+	ExposeAsync("panicAsync", func() int {
+		var m map[string]int
+		m["nil map"] = 1
+		return 0
+	})
+	defer bridge.Delete("panicSync")
+	defer bridge.Delete("panicAsync")
+
+	tests := []struct {
+		name      string
+		expr      string
+		wantValue string
+	}{
+		{"sync", `bridge.panicSync()`, "boom"},
+		{"async", `bridge.panicAsync()`, "assignment to entry in nil map"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result describes the thrown or rejected value, and whether it holds the Go stack trace.
+			run := evalJS(`async (bridge) => {
+				try {
+					await ` + tt.expr + `;
+					return "no error";
+				} catch (e) {
+					return e.name + ": " + e.message + (e.goStack ? " with goStack" : "");
+				}
+			}`)
+
+			var got string
+			if err := mustJSValueToPromise(run.Invoke(bridge.value)).Await(&got); err != nil {
+				t.Fatalf("Await returned error: %v", err)
+			}
+			if want := "GoPanic: panic: " + tt.wantValue + " with goStack"; got != want {
+				t.Errorf("%s threw %q, want %q", tt.expr, got, want)
+			}
+
+			select {
+			case p := <-handled:
+				if msg := p.Error(); msg != "panic: "+tt.wantValue {
+					t.Errorf("the panic handler was called with %q", msg)
+				}
+				if p.Stack == "" {
+					t.Error("the panic handler was called without a stack trace")
+				}
+			case <-time.After(time.Second):
+				t.Error("the panic handler was not called")
+			}
+		})
+	}
+
+	// The program keeps running and the functions can still be called.
+	var got string
+	run := evalJS(`async (bridge) => { try { bridge.panicSync() } catch (e) { return e.name } }`)
+	if err := mustJSValueToPromise(run.Invoke(bridge.value)).Await(&got); err != nil || got != "GoPanic" {
+		t.Errorf("calling panicSync again returned %q, %v", got, err)
+	}
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Error("the panic handler was not called again")
+	}
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	cause := errors.New("cause")
+	if err := (PanicError{Value: cause}); !errors.Is(err, cause) {
+		t.Error("PanicError does not unwrap the error passed to panic")
+	}
+	if err := (PanicError{Value: "cause"}); errors.Unwrap(err) != nil {
+		t.Errorf("PanicError unwrapped a value that is not an error: %v", errors.Unwrap(err))
+	}
+}
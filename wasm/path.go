@@ -0,0 +1,115 @@
+package wasm
+
+import (
+	"reflect"
+	"strconv"
+	"syscall/js"
+)
+
+// DecodeError is an error that occurred while decoding a value nested in the JS value passed to FromJSValue.
+// It records where the failing value is, what it is, and the Go type it could not be decoded into.
+type DecodeError struct {
+	// Path is the location of the failing value in a JSONPath-like syntax, such as items[3].owner.id. The key and the
+	// value of the i-th entry of a JS Map are at [i].key and [i].value.
+	// It is empty if the JS value passed to FromJSValue failed itself.
+	Path string
+	// JSType is the type of the failing value as named by js.Type, such as "string", or "bigint" for a BigInt.
+	JSType string
+	// GoType is the type that the failing value was decoded into.
+	GoType reflect.Type
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *DecodeError) Error() string {
+	msg := "cannot decode JS " + e.JSType + " into " + e.GoType.String()
+	if e.Path != "" {
+		msg += " at " + e.Path
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError wraps the error returned when decoding x into a value of type t, unless it is already a DecodeError.
+func newDecodeError(err error, x js.Value, t reflect.Type) *DecodeError {
+	if de, ok := err.(*DecodeError); ok {
+		return de
+	}
+	return &DecodeError{
+		JSType: jsTypeName(x),
+		GoType: t,
+		Err:    err,
+	}
+}
+
+// withProperty adds the property of a JS object to the beginning of the path of the error returned when decoding the
+// value of the property, x, into a value of type t.
+func withProperty(err error, name string, x js.Value, t reflect.Type) error {
+	de := newDecodeError(err, x, t)
+	de.Path = joinPath(propertySegment(name), de.Path)
+	return de
+}
+
+// withIndex adds the index of a JS array to the beginning of the path of the error returned when decoding the element
+// at the index, x, into a value of type t.
+func withIndex(err error, i int, x js.Value, t reflect.Type) error {
+	de := newDecodeError(err, x, t)
+	de.Path = joinPath("["+strconv.Itoa(i)+"]", de.Path)
+	return de
+}
+
+// withEntry adds the index of a JS Map entry, followed by the part of the entry that failed, "key" or "value", to the
+// beginning of the path of the error returned when decoding that part, x, into a value of type t.
+func withEntry(err error, i int, part string, x js.Value, t reflect.Type) error {
+	de := newDecodeError(err, x, t)
+	de.Path = joinPath("["+strconv.Itoa(i)+"]", joinPath(part, de.Path))
+	return de
+}
+
+// joinPath joins a path segment with the rest of the path.
+func joinPath(segment, path string) string {
+	if path == "" || path[0] == '[' {
+		return segment + path
+	}
+	return segment + "." + path
+}
+
+// propertySegment returns the path segment of the named property.
+// Names that are not identifiers are quoted in brackets, as in ["first name"].
+func propertySegment(name string) string {
+	if !isIdentifier(name) {
+		return "[" + strconv.Quote(name) + "]"
+	}
+	return name
+}
+
+// isIdentifier checks if the provided property name can be written with the dot notation in JS.
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c == '$':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// jsTypeName returns the name of the type of the provided js.Value, including BigInts which js.Type does not support.
+func jsTypeName(x js.Value) string {
+	typ, ok := jsType(x)
+	if !ok {
+		return "bigint"
+	}
+	return typ.String()
+}
@@ -0,0 +1,51 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeErrorPath(t *testing.T) {
+	type owner struct {
+		ID int
+	}
+	type item struct {
+		Owner owner
+	}
+
+	tests := []struct {
+		name   string
+		expr   string
+		into   interface{}
+		path   string
+		jsType string
+		goType reflect.Type
+	}{
+		{"root", `"x"`, new(int), "", "string", reflect.TypeOf(0)},
+		{"nested", `{Items: [{Owner: {ID: "x"}}]}`, new(struct{ Items []item }), "Items[0].Owner.ID", "string",
+			reflect.TypeOf(0)},
+		{"array in array", `[[1, "x"]]`, new([][]int), "[0][1]", "string", reflect.TypeOf(0)},
+		{"quoted property", `{"a b": "x"}`, new(map[string]int), `["a b"]`, "string", reflect.TypeOf(0)},
+		{"object key", `{x: 1}`, new(map[int]int), "x", "string", reflect.TypeOf(0)},
+		{"Map key", `new Map([["x", "a"]])`, new(map[int]string), "[0].key", "string", reflect.TypeOf(0)},
+		{"Map value", `new Map([["a", "x"]])`, new(map[string]int), "[0].value", "string", reflect.TypeOf(0)},
+		{"interface Map key", `new Map([[1, 1], [{}, 2]])`, new(interface{}), "[1].key", "object",
+			interfaceType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromJSValue(evalJS(tt.expr), tt.into)
+
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("FromJSValue returned %v, want a DecodeError", err)
+			}
+			if de.Path != tt.path || de.JSType != tt.jsType || de.GoType != tt.goType {
+				t.Errorf("DecodeError = {Path: %q, JSType: %q, GoType: %v}, want {Path: %q, JSType: %q, GoType: %v}",
+					de.Path, de.JSType, de.GoType, tt.path, tt.jsType, tt.goType)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package wasm
 
 import (
-	"errors"
 	"syscall/js"
 )
 
@@ -19,14 +18,15 @@ func (p *Promise) FromJSValue(value js.Value) error {
 }
 
 // NewPromise returns a promise that is fulfilled or rejected when the provided handler returns.
-// The handler is spawned in its own goroutine.
+// The handler is spawned in its own goroutine. A panic in the handler is recovered and rejects the promise with a
+// PanicError, like in a Go function called by JS.
 func NewPromise(handler func() (interface{}, error)) Promise {
-	resultChan := make(chan interface{})
+	resultChan := make(chan js.Value)
 	errChan := make(chan error)
 
 	// Invoke the handler in a new goroutine.
 	go func() {
-		result, err := handler()
+		result, err := callHandlerRecovered(handler)
 		if err != nil {
 			errChan <- err
 			return
@@ -36,7 +36,7 @@ func NewPromise(handler func() (interface{}, error)) Promise {
 
 	// Create a JS promise handler.
 	var jsHandler js.Func
-	jsHandler = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	jsHandler = newFunc(func(this js.Value, args []js.Value) interface{} {
 		if len(args) < 2 {
 			panic("not enough arguments are passed to the Promise constructor handler")
 		}
@@ -51,43 +51,55 @@ func NewPromise(handler func() (interface{}, error)) Promise {
 		go func() {
 			select {
 			case r := <-resultChan:
-				resolve.Invoke(ToJSValue(r))
+				resolve.Invoke(r)
 			case err := <-errChan:
 				reject.Invoke(NewError(err))
 			}
 
 			// Free up resources now that we are done.
-			jsHandler.Release()
+			releaseFunc(jsHandler)
 		}()
 
 		return nil
 	})
 
-	promise, err := Global().Expect(js.TypeFunction, "Promise")
+	return mustJSValueToPromise(globalConstructor("Promise").New(jsHandler))
+}
+
+// callHandlerRecovered calls the handler of a promise and converts its result to JS, returning a PanicError if it
+// panics and a CycleError if the result refers back to itself.
+func callHandlerRecovered(handler func() (interface{}, error)) (result js.Value, err error) {
+	defer recoverPanic(&err)
+	x, err := handler()
 	if err != nil {
-		panic("Promise constructor not found")
+		return js.Undefined(), err
 	}
-
-	return mustJSValueToPromise(promise.New(jsHandler))
+	return Encode(x)
 }
 
 // Await waits for the Promise. It unmarshals the resolved value to v. An error
 // will be returned if unmarshalling is unsuccessful or the Promise rejects.
-// It is implemented by calling then and catch on JS.
+// The reason of a rejection is returned as a *JSError.
+// It is implemented by calling then on JS, which handles the rejection as well.
 func (p Promise) Await(v interface{}) error {
 	err := make(chan error)
-	p.value.Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	onFulfilled := newFunc(func(this js.Value, args []js.Value) interface{} {
 		if len(args) > 0 && v != nil {
 			err <- FromJSValue(args[0], v)
 			return nil
 		}
 		err <- nil
 		return nil
-	}))
-	p.value.Call("catch", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		err <- errors.New(args[0].Call("toString").String())
+	})
+	onRejected := newFunc(func(this js.Value, args []js.Value) interface{} {
+		err <- newJSError(args[0], nil)
 		return nil
-	}))
+	})
+	p.value.Call("then", onFulfilled, onRejected)
+
+	// Only one of the handlers is ever called, after which both can be freed up.
+	defer releaseFunc(onFulfilled)
+	defer releaseFunc(onRejected)
 	return <-err
 }
 
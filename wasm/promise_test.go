@@ -0,0 +1,42 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewPromise(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cyclic := &node{}
+	cyclic.Next = cyclic
+
+	tests := []struct {
+		name    string
+		handler func() (interface{}, error)
+		want    string
+		wantErr string
+	}{
+		{"resolve", func() (interface{}, error) { return "ok", nil }, "ok", ""},
+		{"reject", func() (interface{}, error) { return nil, errors.New("failure") }, "", "Error: failure"},
+		{"panic", func() (interface{}, error) { panic("boom") }, "", "GoPanic: panic: boom"},
+		{"cycle", func() (interface{}, error) { return cyclic, nil }, "", "Error: " + CycleError{reflect.TypeOf(cyclic)}.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			err := NewPromise(tt.handler).Await(&got)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Await returned error: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("Await returned %v, want %q", err, tt.wantErr)
+			case got != tt.want:
+				t.Errorf("Await resolved to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package wasm
+
+import (
+	"reflect"
+	"syscall/js"
+)
+
+// startDetectingCyclesAfter is the depth of pointers, maps and slices after which cycles are looked for.
+// It keeps the bookkeeping out of the way of the common, shallow values.
+const startDetectingCyclesAfter = 1000
+
+// CycleError is an error where a Go value refers back to itself through pointers, maps or slices, which ToJSValue
+// would otherwise follow forever.
+//
+// Encode, Expose and their Encoder counterparts return it, and Go functions called by JS throw it. ToJSValue,
+// Encoder.ToJSValue, Object.Set, Object.SetIndex and Object.SetSymbol have no error to return, so they panic with it.
+type CycleError struct {
+	Type reflect.Type
+}
+
+// Error implements error.
+func (e CycleError) Error() string {
+	return "cannot convert " + e.Type.String() + " to a JS value: encountered a cycle"
+}
+
+// recoverCycle recovers from a CycleError panic, storing it in err.
+// It must be deferred directly by the function that returns err. Other panics are resumed.
+func recoverCycle(err *error) {
+	if r := recover(); r != nil {
+		cycleErr, ok := r.(CycleError)
+		if !ok {
+			panic(r)
+		}
+		*err = cycleErr
+	}
+}
+
+// refKey identifies the memory referenced by a pointer, a map or a slice.
+// The type tells a struct apart from its first field, and the length tells a slice apart from its subslices.
+type refKey struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// newRefKey returns the refKey of the provided pointer, map or slice.
+func newRefKey(v reflect.Value) refKey {
+	key := refKey{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	return key
+}
+
+// enter marks the start of the conversion of the provided pointer, map or slice.
+// It panics with a CycleError if the same value is already being converted further up.
+func (e *encodeState) enter(key refKey) {
+	e.ptrLevel++
+	if e.ptrLevel <= startDetectingCyclesAfter {
+		return
+	}
+
+	if e.ptrSeen == nil {
+		e.ptrSeen = map[refKey]struct{}{}
+	}
+	if _, ok := e.ptrSeen[key]; ok {
+		panic(CycleError{key.typ})
+	}
+	e.ptrSeen[key] = struct{}{}
+}
+
+// leave marks the end of the conversion of the provided pointer, map or slice.
+func (e *encodeState) leave(key refKey) {
+	if e.ptrLevel > startDetectingCyclesAfter {
+		delete(e.ptrSeen, key)
+	}
+	e.ptrLevel--
+}
+
+// encodeRef converts the provided pointer or map with enc.
+// With the References option, a pointer or map converted before returns the same JS value again.
+func (e *encodeState) encodeRef(v reflect.Value, enc encoderFunc) js.Value {
+	if v.Pointer() == 0 {
+		return enc(e, v)
+	}
+
+	key := newRefKey(v)
+	if e.opts.References {
+		if e.refs == nil {
+			e.refs = map[refKey]js.Value{}
+		}
+		if x, ok := e.refs[key]; ok {
+			return x
+		}
+		e.pendingRefs = append(e.pendingRefs, key)
+	}
+
+	e.enter(key)
+	x := enc(e, v)
+	e.leave(key)
+
+	if e.opts.References {
+		// The value did not create an object of its own, such as a pointer to a number.
+		if n := len(e.pendingRefs); n > 0 && e.pendingRefs[n-1] == key {
+			e.pendingRefs = e.pendingRefs[:n-1]
+		}
+		if _, ok := e.refs[key]; !ok {
+			e.refs[key] = x
+		}
+	}
+	return x
+}
+
+// addRef records the provided JS object as the conversion of the pointers and maps waiting for one.
+// It is called as soon as an object is created, before its contents are converted, so that cycles lead back to it.
+func (e *encodeState) addRef(obj js.Value) {
+	for _, key := range e.pendingRefs {
+		e.refs[key] = obj
+	}
+	e.pendingRefs = e.pendingRefs[:0]
+}
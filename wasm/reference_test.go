@@ -0,0 +1,97 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	shared := &node{Name: "shared"}
+	sharedPtr := &shared
+	slice := []int{1, 2}
+	m := map[string]int{"a": 1}
+
+	tests := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"pointer", shared, shared},
+		{"pointer to slice", &slice, &slice},
+		{"map", m, m},
+		{"pointer to map", &m, &m},
+		{"pointer to pointer", sharedPtr, shared},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := map[string]interface{}{"a": tt.a, "b": tt.b}
+
+			x, err := NewEncoder(EncoderOptions{References: true}).Encode(value)
+			if err != nil {
+				t.Fatalf("Encode with References returned error: %v", err)
+			}
+			if !x.Get("a").Equal(x.Get("b")) {
+				t.Error("Encode with References converted the same value to different JS values")
+			}
+
+			x, err = Encode(value)
+			if err != nil {
+				t.Fatalf("Encode returned error: %v", err)
+			}
+			if x.Get("a").Equal(x.Get("b")) {
+				t.Error("Encode without References converted the same value to the same JS value")
+			}
+		})
+	}
+
+	t.Run("cycle", func(t *testing.T) {
+		cyclic := &node{Name: "cyclic"}
+		cyclic.Next = cyclic
+
+		x, err := NewEncoder(EncoderOptions{References: true}).Encode(cyclic)
+		if err != nil {
+			t.Fatalf("Encode with References returned error: %v", err)
+		}
+		if !x.Get("Next").Equal(x) {
+			t.Error("Encode with References did not preserve the cycle")
+		}
+	})
+}
+
+func TestReferencesCycleError(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cyclicPtr := &node{}
+	cyclicPtr.Next = cyclicPtr
+
+	cyclicMap := map[string]interface{}{}
+	cyclicMap["self"] = cyclicMap
+
+	cyclicSlice := []interface{}{nil}
+	cyclicSlice[0] = cyclicSlice
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		references bool
+	}{
+		{"pointer", cyclicPtr, false},
+		{"map", cyclicMap, false},
+		{"slice", cyclicSlice, false},
+		{"slice with References", cyclicSlice, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEncoder(EncoderOptions{References: tt.references}).Encode(tt.value)
+			if !errors.As(err, new(CycleError)) {
+				t.Errorf("Encode returned %v, want a CycleError", err)
+			}
+		})
+	}
+}
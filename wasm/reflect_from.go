@@ -1,9 +1,13 @@
 package wasm
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"sync"
 	"syscall/js"
 	"time"
 )
@@ -48,6 +52,74 @@ func (e InvalidArrayError) Error() string {
 	)
 }
 
+// MissingFieldError is an error where a JS object lacks a property that is marked as required in the wasm tag of a
+// struct field.
+type MissingFieldError struct {
+	Field    string
+	Property string
+}
+
+// Error implements error.
+func (e MissingFieldError) Error() string {
+	return "invalid unmarshalling: missing required property " + e.Property + " for field " + e.Field
+}
+
+// InvalidNumberError is an error where a JS number cannot be represented by the Go type when decoding with the
+// StrictNumbers option.
+type InvalidNumberError struct {
+	Value  float64
+	GoType reflect.Type
+}
+
+// Error implements error.
+func (e InvalidNumberError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: number %v cannot be represented by %s", e.Value, e.GoType)
+}
+
+// UnknownFieldError is an error where a JS object has a property that does not match any field of the struct it is
+// decoded into, when decoding with the DisallowUnknownFields option.
+type UnknownFieldError struct {
+	Property string
+	GoType   reflect.Type
+}
+
+// Error implements error.
+func (e UnknownFieldError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: unknown property %q for %s", e.Property, e.GoType)
+}
+
+// DepthError is an error where JS objects and arrays are nested deeper than the MaxDepth option allows.
+type DepthError struct {
+	MaxDepth int
+}
+
+// Error implements error.
+func (e DepthError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: JS value nested deeper than %d levels", e.MaxDepth)
+}
+
+// ArrayLengthError is an error where a JS array is longer than the MaxArrayLength option allows.
+type ArrayLengthError struct {
+	MaxLength int
+	Length    int
+}
+
+// Error implements error.
+func (e ArrayLengthError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: JS array of length %d is longer than %d", e.Length, e.MaxLength)
+}
+
+// InvalidMapKeyError is an error where a key of a JS Map is decoded into a Go value that cannot be a map key, such as
+// a JS object decoded into an interface{}.
+type InvalidMapKeyError struct {
+	GoType reflect.Type
+}
+
+// Error implements error.
+func (e InvalidMapKeyError) Error() string {
+	return "invalid unmarshalling: cannot use " + e.GoType.String() + " as a map key"
+}
+
 // Decoder is an interface which manually decodes js.Value on its own.
 // It overrides in FromJSValue.
 type Decoder interface {
@@ -56,83 +128,262 @@ type Decoder interface {
 
 // FromJSValue converts a given js.Value to the Go equivalent.
 // The new value of 'out' is undefined if FromJSValue returns an error.
+// Decoding errors are returned as a *DecodeError, which holds the path of the failing value, such as
+// items[3].owner.id, its JS type and the Go type it could not be decoded into.
+// The *DecodeError wraps the error that caused it, so comparing the returned error with == against a sentinel error or
+// asserting its type no longer matches: use errors.Is and errors.As instead, which unwrap it.
+//
+// Struct fields are filled from the properties named by their wasm tag, the same way as ToJSValue names them. A field
+// tagged with the required option makes FromJSValue return a MissingFieldError if the property is missing or
+// undefined, while the string option accepts booleans and numbers encoded as JS strings.
+//
+// JS objects can be decoded into an interface other than interface{} only if its concrete types are registered with
+// RegisterVariants, which picks the type by the discriminator property of the object.
+//
+// Any JS value, such as an Error, can be decoded into a JSError, which keeps its name, message, stack and cause. Such
+// values are decoded into a *JSError when the target is an error.
+//
+// JS BigInts can be decoded into integers that can hold them, floats and big.Int. They are decoded into a *big.Int when
+// the target is an interface{}.
+//
+// Maps can be decoded from JS objects, in which case their keys must be strings, integers, interface{} or implement
+// encoding.TextUnmarshaler, and from JS Map instances, in which case the keys are decoded like any other value.
+//
+// Types whose pointer does not implement Decoder fall back to encoding.TextUnmarshaler for JS strings and to
+// json.Unmarshaler, which receives the result of JSON.stringify, for any other value.
+//
+// TypedArrays and ArrayBuffers can be unmarshalled into slices and arrays. When the element type of the TypedArray
+// matches the Go element type, such as a Uint8Array and a []byte, its memory is copied in bulk.
 //
 // When a JS function is unmarshalled into a Go function with only one return value, the returned JS value is casted
 // into the type of the return value. If the conversion fails, the function call panics.
 //
 // When a JS function is unmarshalled into a Go function with two return values, the second one being error, the
 // conversion error is returned instead.
+//
+// JS async iterables, such as async generators, and regular iterables can be unmarshalled into a receive-only channel.
+// A value is requested from JS only once the previous one is received, and the channel is closed when the iteration
+// ends, rejects or yields a value that cannot be decoded. StopIterator ends the iteration early from Go and reports
+// the error that ended it.
+//
+// Undefined values, including missing properties, leave the existing Go value untouched. A ValueDecoder with the
+// ZeroUndefined option zeroes it instead. The other options of a ValueDecoder reject input that is not trusted, such as
+// unknown properties, deeply nested values and long arrays.
+//
+// Numbers are truncated or wrapped to fit integer types. A ValueDecoder with the StrictNumbers option returns an
+// InvalidNumberError instead.
 func FromJSValue(x js.Value, out interface{}) error {
+	return defaultDecoder.FromJSValue(x, out)
+}
+
+// DecoderOptions configures how a ValueDecoder converts JS values to Go.
+type DecoderOptions struct {
+	// StrictNumbers rejects JS numbers that the Go type cannot represent exactly: fractions, NaN, Infinity and values
+	// out of range for integers, as well as NaN, Infinity and values out of range for floats.
+	StrictNumbers bool
+
+	// DisallowUnknownFields makes decoding into a struct return an UnknownFieldError when the JS object has a property
+	// that does not match any field of the struct, or the discriminator property of a registered variant. Methods and
+	// accessors exposed on the JS object are not fields, so they are unknown as well.
+	DisallowUnknownFields bool
+
+	// MaxDepth limits how deep JS objects and arrays can be nested, returning a DepthError beyond it.
+	// Zero means no limit.
+	MaxDepth int
+
+	// MaxArrayLength limits the length of JS arrays and TypedArrays, returning an ArrayLengthError beyond it.
+	// Zero means no limit.
+	MaxArrayLength int
+
+	// ZeroUndefined sets the Go value to its zero value when the JS value is undefined, including missing properties
+	// of an object decoded into a struct. By default, the existing Go value is kept.
+	ZeroUndefined bool
+}
+
+// ValueDecoder converts JS values to Go according to its options, much like json.Decoder does for JSON.
+type ValueDecoder struct {
+	opts DecoderOptions
+}
+
+// defaultDecoder is the ValueDecoder used by FromJSValue.
+var defaultDecoder = NewDecoder(DecoderOptions{})
+
+// NewDecoder returns a ValueDecoder with the provided options.
+func NewDecoder(opts DecoderOptions) *ValueDecoder {
+	return &ValueDecoder{opts}
+}
+
+// FromJSValue converts a given js.Value to the Go equivalent, as described by the package-level FromJSValue.
+func (dec *ValueDecoder) FromJSValue(x js.Value, out interface{}) error {
 	v := reflect.ValueOf(out)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return &InvalidFromJSValueError{reflect.TypeOf(v)}
+		return &InvalidFromJSValueError{reflect.TypeOf(out)}
 	}
 
-	return decodeValue(x, v.Elem())
+	d := &decodeState{opts: dec.opts}
+	if err := decodeValue(d, x, v.Elem()); err != nil {
+		return newDecodeError(err, x, v.Elem().Type())
+	}
+	return nil
 }
 
+// decodeState holds the state of a single conversion from JS to Go.
+type decodeState struct {
+	opts DecoderOptions
+
+	// Depth of the JS objects and arrays being decoded.
+	depth int
+}
+
+// enter marks the start of the decoding of a JS object or array.
+// It returns a DepthError if it is nested deeper than the MaxDepth option allows.
+func (d *decodeState) enter() error {
+	d.depth++
+	if d.opts.MaxDepth > 0 && d.depth > d.opts.MaxDepth {
+		return DepthError{d.opts.MaxDepth}
+	}
+	return nil
+}
+
+// leave marks the end of the decoding of a JS object or array.
+func (d *decodeState) leave() {
+	d.depth--
+}
+
+// checkLength returns an ArrayLengthError if the length of a JS array is above the MaxArrayLength option.
+func (d *decodeState) checkLength(length int) error {
+	if d.opts.MaxArrayLength > 0 && length > d.opts.MaxArrayLength {
+		return ArrayLengthError{d.opts.MaxArrayLength, length}
+	}
+	return nil
+}
+
+// decoderFunc decodes a js.Value into a reflect.Value of a specific type.
+type decoderFunc func(d *decodeState, x js.Value, v reflect.Value) error
+
+// decoderCache holds the compiled decoderFunc of every type decoded so far.
+var decoderCache sync.Map // map[reflect.Type]decoderFunc
+
+var decoderType = reflect.TypeOf((*Decoder)(nil)).Elem()
+
 // decodeValue decodes the provided js.Value into the provided reflect.Value.
-func decodeValue(x js.Value, v reflect.Value) error {
-	// If we have undefined or null, we need to be able to set to the pointer itself.
-	// All code beyond this point are pointer-unaware so we handle undefined or null first.
-	switch x.Type() {
-	case js.TypeUndefined:
-		// Keep the existing value if it is undefined.
-		return nil
-	case js.TypeNull:
-		return decodeNothing(v)
+func decodeValue(d *decodeState, x js.Value, v reflect.Value) error {
+	return typeDecoder(v.Type())(d, x, v)
+}
+
+// typeDecoder returns the cached decoderFunc of the provided type, compiling it if necessary.
+func typeDecoder(t reflect.Type) decoderFunc {
+	if fi, ok := decoderCache.Load(t); ok {
+		return fi.(decoderFunc)
 	}
 
-	// Implementations of Decoder are probably on pointer so do it before pointer code.
-	if d, ok := v.Addr().Interface().(Decoder); ok {
-		return d.FromJSValue(x)
+	// To deal with recursive types, populate the cache with an indirect func before compiling the decoder.
+	// It waits on the real func to be ready before using it.
+	var (
+		wg sync.WaitGroup
+		f  decoderFunc
+	)
+	wg.Add(1)
+	fi, loaded := decoderCache.LoadOrStore(t, decoderFunc(func(d *decodeState, x js.Value, v reflect.Value) error {
+		wg.Wait()
+		return f(d, x, v)
+	}))
+	if loaded {
+		return fi.(decoderFunc)
 	}
 
-	// Make sure everything is initialized and indirect it.
-	// This prevents other decode functions from having to handle pointers.
-	if v.Kind() == reflect.Ptr {
-		initializePointerIfNil(v)
-		v = reflect.Indirect(v)
+	f = newTypeDecoder(t)
+	wg.Done()
+	decoderCache.Store(t, f)
+	return f
+}
+
+// newTypeDecoder compiles the decoderFunc of the provided type.
+func newTypeDecoder(t reflect.Type) decoderFunc {
+	dec := newValueDecoder(t)
+
+	// BigInts are handled separately as js.Value.Type does not support them.
+	if inspectsJSType(t) {
+		dec = newBigIntCheckDecoder(dec, newBigIntDecoder(t, dec))
 	}
 
-	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
-		// It's a interface{} so we just create the easiest Go representation we can in createInterface.
-		res := createInterface(x)
-		if res != nil {
-			v.Set(reflect.ValueOf(res))
+	// If we have undefined or null, we need to be able to set to the pointer itself.
+	// The compiled decoders are pointer-unaware so we handle undefined or null first.
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		switch {
+		case x.IsUndefined():
+			// Keep the existing value if it is undefined, unless asked otherwise.
+			if d.opts.ZeroUndefined {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return nil
+		case x.IsNull():
+			return decodeNothing(v)
 		}
-		return nil
+		return dec(d, x, v)
 	}
+}
 
-	// Directly set v if it's a js.Value.
-	if _, ok := v.Interface().(js.Value); ok {
-		v.Set(reflect.ValueOf(x))
-		return nil
+// newValueDecoder compiles the decoderFunc of the provided type for values other than undefined and null.
+func newValueDecoder(t reflect.Type) decoderFunc {
+	// Implementations of Decoder are probably on pointer so do it before pointer code.
+	if reflect.PtrTo(t).Implements(decoderType) {
+		return decodeWithDecoder
 	}
 
-	// Go the reflection route.
-	switch x.Type() {
-	case js.TypeBoolean:
-		return decodeBoolean(x, v)
-	case js.TypeNumber:
-		return decodeNumber(x, v)
-	case js.TypeString:
-		return decodeString(x, v)
-	case js.TypeSymbol:
-		return decodeSymbol(x, v)
-	case js.TypeObject:
-		if isArray(x) {
-			return decodeArray(x, v)
+	switch t {
+	case jsValueType:
+		return decodeJSValue
+	case timeType:
+		return decodeDate
+	}
+
+	kindDecoder := func() decoderFunc {
+		return newKindDecoder(t)
+	}
+	if dec := newUnmarshalerDecoder(t, kindDecoder); dec != nil {
+		return dec
+	}
+	return kindDecoder()
+}
+
+// newKindDecoder compiles the decoderFunc of the provided type based on its kind.
+func newKindDecoder(t reflect.Type) decoderFunc {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return newPtrDecoder(t)
+	case reflect.Interface:
+		if vs, ok := typeVariants(t); ok {
+			return newVariantDecoder(t, vs)
 		}
-		if isDate(x) {
-			return decodeDate(x, v)
+		if t == errorType {
+			return decodeError
 		}
-		return decodeObject(x, v)
-	case js.TypeFunction:
-		return decodeFunction(x, v)
-	default:
-		panic("unknown JS type: " + x.Type().String())
+		if t.NumMethod() == 0 {
+			return decodeInterface
+		}
+	case reflect.Bool:
+		return decodeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return decodeNumber
+	case reflect.String:
+		return decodeString
+	case reflect.Array, reflect.Slice:
+		return decodeArrayLike
+	case reflect.Struct:
+		return newStructDecoder(t)
+	case reflect.Map:
+		return newMapDecoder(t)
+	case reflect.Complex64, reflect.Complex128:
+		return decodeObjectIntoComplex
+	case reflect.Func:
+		return decodeFunction
+	case reflect.Chan:
+		return newChanDecoder(t)
 	}
+	return decodeUnsupported
 }
 
 // decodeNothing decodes an undefined or a null into the provided reflect.Value.
@@ -144,17 +395,75 @@ func decodeNothing(v reflect.Value) error {
 	return nil
 }
 
+// decodeWithDecoder lets the Decoder implementation of the provided reflect.Value decode the js.Value.
+func decodeWithDecoder(d *decodeState, x js.Value, v reflect.Value) error {
+	return v.Addr().Interface().(Decoder).FromJSValue(x)
+}
+
+// decodeJSValue directly sets the provided reflect.Value of type js.Value.
+func decodeJSValue(d *decodeState, x js.Value, v reflect.Value) error {
+	v.Set(reflect.ValueOf(x))
+	return nil
+}
+
+// newPtrDecoder returns a decoderFunc that initializes the pointer if it is nil and decodes into the pointed value.
+// This prevents other decode functions from having to handle pointers.
+func newPtrDecoder(t reflect.Type) decoderFunc {
+	elemDec := typeDecoder(t.Elem())
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return elemDec(d, x, v.Elem())
+	}
+}
+
+// decodeInterface decodes the provided js.Value into an interface{}.
+func decodeInterface(d *decodeState, x js.Value, v reflect.Value) error {
+	// It's a interface{} so we just create the easiest Go representation we can in createInterface.
+	res, err := createInterface(d, x)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		v.Set(reflect.ValueOf(res))
+	}
+	return nil
+}
+
+// decodeError decodes any JS value, such as a thrown Error, into an error holding a *JSError.
+func decodeError(d *decodeState, x js.Value, v reflect.Value) error {
+	v.Set(reflect.ValueOf(newJSError(x, nil)))
+	return nil
+}
+
+// decodeUnsupported returns an error for Go types that no JS value can be decoded into.
+func decodeUnsupported(d *decodeState, x js.Value, v reflect.Value) error {
+	return InvalidTypeError{x.Type(), v.Type()}
+}
+
 // decodeBoolean decodes a bool into the provided reflect.Value.
-func decodeBoolean(x js.Value, v reflect.Value) error {
-	if v.Kind() != reflect.Bool {
-		return InvalidTypeError{js.TypeBoolean, v.Type()}
+func decodeBoolean(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() != js.TypeBoolean {
+		return InvalidTypeError{x.Type(), v.Type()}
 	}
 	v.SetBool(x.Bool())
 	return nil
 }
 
 // decodeNumber decodes a JS number into the provided reflect.Value, truncating as necessary.
-func decodeNumber(x js.Value, v reflect.Value) error {
+// With the StrictNumbers option, numbers that the Go type cannot represent return an InvalidNumberError instead.
+func decodeNumber(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() != js.TypeNumber {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+
+	if d.opts.StrictNumbers {
+		if err := checkNumber(x.Float(), v.Type()); err != nil {
+			return err
+		}
+	}
+
 	switch v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		v.SetInt(int64(x.Float()))
@@ -168,24 +477,67 @@ func decodeNumber(x js.Value, v reflect.Value) error {
 	return nil
 }
 
+// checkNumber returns an InvalidNumberError if the provided number cannot be represented exactly by the numeric type.
+func checkNumber(f float64, t reflect.Type) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return InvalidNumberError{f, t}
+	}
+
+	var inRange bool
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		limit := math.Ldexp(1, t.Bits()-1)
+		inRange = math.Trunc(f) == f && f >= -limit && f < limit
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		limit := math.Ldexp(1, t.Bits())
+		inRange = math.Trunc(f) == f && f >= 0 && f < limit
+	case reflect.Float32:
+		inRange = math.Abs(f) <= math.MaxFloat32
+	default:
+		inRange = true
+	}
+
+	if !inRange {
+		return InvalidNumberError{f, t}
+	}
+	return nil
+}
+
 // decodeString decodes a JS string into the provided reflect.Value.
-func decodeString(x js.Value, v reflect.Value) error {
-	if v.Kind() != reflect.String {
-		return InvalidTypeError{js.TypeString, v.Type()}
+func decodeString(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() != js.TypeString {
+		return InvalidTypeError{x.Type(), v.Type()}
 	}
 	v.SetString(x.String())
 	return nil
 }
 
-// decodeSymbol decodes a JS symbol into the provided reflect.Value.
-func decodeSymbol(x js.Value, v reflect.Value) error {
-	// TODO Decode it into a symbol type.
-	return InvalidTypeError{js.TypeSymbol, v.Type()}
+// decodeArrayLike decodes a JS array, TypedArray or ArrayBuffer into the provided reflect.Value.
+func decodeArrayLike(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() != js.TypeObject {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
+
+	if isArray(x) {
+		return decodeArray(d, x, v)
+	}
+	if isTypedArray(x) {
+		return decodeTypedArray(d, x, v)
+	}
+	return InvalidTypeError{js.TypeObject, v.Type()}
 }
 
 // decodeArray decodes a JS array into the provided reflect.Value.
-func decodeArray(x js.Value, v reflect.Value) error {
+func decodeArray(d *decodeState, x js.Value, v reflect.Value) error {
 	jsLen := x.Length()
+	if err := d.checkLength(jsLen); err != nil {
+		return err
+	}
 
 	switch v.Kind() {
 	case reflect.Array:
@@ -199,121 +551,326 @@ func decodeArray(x js.Value, v reflect.Value) error {
 		return InvalidTypeError{js.TypeObject, v.Type()}
 	}
 
+	elemDec := typeDecoder(v.Type().Elem())
 	for i := 0; i < jsLen; i++ {
-		err := FromJSValue(x.Index(i), v.Index(i).Addr().Interface())
+		elem := x.Index(i)
+		err := elemDec(d, elem, v.Index(i))
 		if err != nil {
-			return err
+			return withIndex(err, i, elem, v.Type().Elem())
 		}
 	}
 	return nil
 }
 
 // decodeDate decodes a JS date into the provided reflect.Value.
-func decodeDate(x js.Value, v reflect.Value) error {
-	t, ok := v.Addr().Interface().(*time.Time)
-	if !ok {
-		return InvalidTypeError{js.TypeObject, v.Type()}
+// Strings are parsed as RFC 3339 timestamps.
+func decodeDate(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() == js.TypeString {
+		return v.Addr().Interface().(*time.Time).UnmarshalText([]byte(x.String()))
 	}
+	if x.Type() != js.TypeObject || !isDate(x) {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+
 	millis := x.Call("getTime").Int()
-	*t = time.UnixMilli(int64(millis))
+	v.Set(reflect.ValueOf(time.UnixMilli(int64(millis))))
 	return nil
 }
 
-// decodeObject decodes a JS object into the provided reflect.Value.
-func decodeObject(x js.Value, v reflect.Value) error {
-	switch v.Kind() {
-	case reflect.Struct:
-		return decodeObjectIntoStruct(x, v)
-	case reflect.Map:
-		return decodeObjectIntoMap(x, v)
-	case reflect.Complex64, reflect.Complex128:
-		return decodeObjectIntoComplex(x, v)
-	default:
-		return InvalidTypeError{js.TypeObject, v.Type()}
-	}
+// isObject checks if the provided js.Value is an object and not an array, which is what structs, maps and complex
+// numbers are decoded from.
+func isObject(x js.Value) bool {
+	return x.Type() == js.TypeObject && !isArray(x)
 }
 
-// decodeObject decodes a JS object into the provided reflect.Value struct.
-func decodeObjectIntoStruct(x js.Value, v reflect.Value) error {
-	for i := 0; i < v.Type().NumField(); i++ {
-		fieldType := v.Type().Field(i)
-		if fieldType.PkgPath != "" {
-			continue
+// structDecoder decodes a JS object into a struct using the fields of its type computed ahead of time.
+type structDecoder struct {
+	fields    []field
+	fieldDecs []decoderFunc
+
+	// Names of the fields and of the discriminator properties of registered variants, which are not unknown to
+	// DisallowUnknownFields. Methods and accessors are not fields, so objects setting them are rejected.
+	known map[string]bool
+}
+
+// newStructDecoder returns a decoderFunc that decodes a JS object into a struct.
+func newStructDecoder(t reflect.Type) decoderFunc {
+	return compileStructDecoder(t).decode
+}
+
+// compileStructDecoder computes the fields of the provided struct type and their decoders.
+func compileStructDecoder(t reflect.Type) structDecoder {
+	sd := structDecoder{
+		fields: typeFields(t),
+		known:  map[string]bool{},
+	}
+	for _, f := range sd.fields {
+		if f.quoted {
+			sd.fieldDecs = append(sd.fieldDecs, newQuotedDecoder(f.typ))
+		} else {
+			sd.fieldDecs = append(sd.fieldDecs, typeDecoder(f.typ))
 		}
+		sd.known[f.name] = true
+	}
+	for _, property := range variantProperties(t) {
+		sd.known[property] = true
+	}
+	return sd
+}
+
+// decode decodes a JS object into the provided reflect.Value struct.
+func (sd structDecoder) decode(d *decodeState, x js.Value, v reflect.Value) error {
+	if !isObject(x) {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
 
-		name := fieldType.Name
-		tagName, tagOK := fieldType.Tag.Lookup("wasm")
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
 
-		if tagOK {
-			if tagName == "-" {
-				continue
+	if d.opts.DisallowUnknownFields {
+		for _, k := range objectKeys(x) {
+			if !sd.known[k] {
+				return UnknownFieldError{Property: k, GoType: v.Type()}
 			}
-			name = tagName
 		}
+	}
 
-		err := decodeValue(x.Get(name), v.Field(i))
-		if err != nil {
-			if tagOK {
-				return fmt.Errorf("in field %s (JS %s): %w", fieldType.Name, tagName, err)
+	for i, f := range sd.fields {
+		value := x.Get(f.name)
+		if f.required && value.IsUndefined() {
+			return MissingFieldError{Field: f.goName, Property: f.name}
+		}
+
+		if value.IsUndefined() {
+			// Keep the existing value without allocating embedded pointers on the way, or zero it if asked to.
+			if d.opts.ZeroUndefined {
+				if fv, _ := fieldByIndex(v, f.index, false); fv.IsValid() {
+					fv.Set(reflect.Zero(fv.Type()))
+				}
 			}
-			return fmt.Errorf("in field %s: %w", fieldType.Name, err)
+			continue
+		}
+
+		if err := sd.decodeField(d, i, value, v); err != nil {
+			return withProperty(err, f.name, value, f.typ)
 		}
 	}
 
 	return nil
 }
 
-func decodeObjectIntoMap(x js.Value, v reflect.Value) error {
-	mapType := v.Type()
-	keyType := mapType.Key()
-	valType := mapType.Elem()
+// decodeField decodes the provided js.Value into the i-th field of the struct, honoring the options of its wasm tag.
+// Nil embedded pointers are allocated on the way to the field.
+func (sd structDecoder) decodeField(d *decodeState, i int, x js.Value, v reflect.Value) error {
+	fv, err := fieldByIndex(v, sd.fields[i].index, true)
+	if err != nil {
+		return err
+	}
 
-	switch keyType.Kind() {
-	case reflect.String:
-	case reflect.Interface:
-		if keyType.NumMethod() != 0 {
-			return InvalidTypeError{js.TypeObject, mapType}
+	return sd.fieldDecs[i](d, x, fv)
+}
+
+// newQuotedDecoder returns the decoderFunc of a field with the string option, whose type is a boolean, a number or a
+// pointer to one. JS strings holding a boolean or a number are parsed into it, while other values are decoded as usual.
+func newQuotedDecoder(t reflect.Type) decoderFunc {
+	dec := typeDecoder(t)
+	elem := t
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	valueDec := newValueDecoder(elem)
+	bigIntDec := newBigIntDecoder(elem, valueDec)
+
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if x.IsUndefined() || x.IsNull() {
+			return dec(d, x, v)
 		}
-	default:
-		return InvalidTypeError{js.TypeObject, mapType}
+
+		if v.Kind() == reflect.Ptr {
+			initializePointerIfNil(v)
+			v = reflect.Indirect(v)
+		}
+
+		typ, ok := jsType(x)
+		switch {
+		case !ok:
+			return bigIntDec(d, x, v)
+		case typ != js.TypeString:
+			return valueDec(d, x, v)
+		}
+		return decodeQuoted(x.String(), v)
 	}
+}
 
-	// TODO: Use Object API
-	obj, err := Global().Get("Object")
-	if err != nil {
-		panic("Object not found")
+// decodeQuoted parses a boolean or a number held by a JS string into the provided reflect.Value.
+func decodeQuoted(s string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return InvalidTypeError{js.TypeString, v.Type()}
 	}
+	return nil
+}
 
-	var keys []string
-	err = FromJSValue(obj.Call("keys", x), &keys)
-	if err != nil {
-		panic("Object.keys returned non-string-array.")
+// newMapDecoder returns a decoderFunc that decodes a JS Map or a JS object into a map.
+func newMapDecoder(t reflect.Type) decoderFunc {
+	keyDec := typeDecoder(t.Key())
+	elemDec := typeDecoder(t.Elem())
+	keyNameDec := newKeyNameDecoder(t.Key())
+
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if !isObject(x) {
+			return InvalidTypeError{x.Type(), t}
+		}
+
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+
+		if isJSMap(x) {
+			return decodeJSMap(d, x, v, keyDec, elemDec)
+		}
+		if keyNameDec == nil {
+			return InvalidTypeError{js.TypeObject, t}
+		}
+
+		keys := objectKeys(x)
+		v.Set(reflect.MakeMapWithSize(t, len(keys)))
+
+		for _, k := range keys {
+			elem := x.Get(k)
+			key, err := keyNameDec(k)
+			if err != nil {
+				return withProperty(err, k, js.ValueOf(k), t.Key())
+			}
+
+			value := reflect.New(t.Elem()).Elem()
+			err = elemDec(d, elem, value)
+			if err != nil {
+				return withProperty(err, k, elem, t.Elem())
+			}
+
+			v.SetMapIndex(key, value)
+		}
+		return nil
 	}
+}
+
+// decodeJSMap decodes the entries of a JS Map into the provided reflect.Value map.
+func decodeJSMap(d *decodeState, x js.Value, v reflect.Value, keyDec, elemDec decoderFunc) error {
+	mapType := v.Type()
+	entries := globalConstructor("Array").Call("from", x)
+	v.Set(reflect.MakeMapWithSize(mapType, entries.Length()))
 
-	v.Set(reflect.MakeMapWithSize(mapType, len(keys)))
+	for i := 0; i < entries.Length(); i++ {
+		entry := entries.Index(i)
 
-	for _, k := range keys {
-		valuePtr := reflect.New(valType).Interface()
-		err := FromJSValue(x.Get(k), valuePtr)
+		key := reflect.New(mapType.Key()).Elem()
+		err := keyDec(d, entry.Index(0), key)
 		if err != nil {
-			return err
+			return withEntry(err, i, "key", entry.Index(0), mapType.Key())
+		}
+		// An interface key can hold a value that cannot be hashed, such as the map decoded from an object key.
+		if key.Kind() == reflect.Interface && !key.IsNil() && !key.Elem().Type().Comparable() {
+			return withEntry(InvalidMapKeyError{key.Elem().Type()}, i, "key", entry.Index(0), mapType.Key())
 		}
 
-		v.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(valuePtr).Elem())
+		value := reflect.New(mapType.Elem()).Elem()
+		err = elemDec(d, entry.Index(1), value)
+		if err != nil {
+			return withEntry(err, i, "value", entry.Index(1), mapType.Elem())
+		}
+
+		v.SetMapIndex(key, value)
 	}
 	return nil
 }
 
+// newKeyNameDecoder returns a function converting the name of a JS property to a map key of the provided type.
+// Keys can be strings, integers, an interface{} or implement encoding.TextUnmarshaler.
+// It returns nil if the type cannot be used as the key of a map decoded from a JS object.
+func newKeyNameDecoder(t reflect.Type) func(string) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return func(name string) (reflect.Value, error) {
+			key := reflect.New(t)
+			err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name))
+			return key.Elem(), err
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(name string) (reflect.Value, error) {
+			return reflect.ValueOf(name).Convert(t), nil
+		}
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return nil
+		}
+		return func(name string) (reflect.Value, error) {
+			return reflect.ValueOf(name), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(name string) (reflect.Value, error) {
+			n, err := strconv.ParseInt(name, 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(name string) (reflect.Value, error) {
+			n, err := strconv.ParseUint(name, 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	default:
+		return nil
+	}
+}
+
 // decodeObjectIntoComplex decodes the provided object into a complex number.
-func decodeObjectIntoComplex(x js.Value, v reflect.Value) error {
+func decodeObjectIntoComplex(d *decodeState, x js.Value, v reflect.Value) error {
+	if !isObject(x) {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+
 	var r, i float64
-	err := FromJSValue(x.Get("real"), &r)
+	err := decodeValue(d, x.Get("real"), reflect.ValueOf(&r).Elem())
 	if err != nil {
-		return err
+		return withProperty(err, "real", x.Get("real"), reflect.TypeOf(r))
 	}
-	err = FromJSValue(x.Get("imag"), &i)
+	err = decodeValue(d, x.Get("imag"), reflect.ValueOf(&i).Elem())
 	if err != nil {
-		return err
+		return withProperty(err, "imag", x.Get("imag"), reflect.TypeOf(i))
 	}
 
 	v.SetComplex(complex(r, i))
@@ -321,7 +878,11 @@ func decodeObjectIntoComplex(x js.Value, v reflect.Value) error {
 }
 
 // decodeFunction decodes a JS function into the provided reflect.Value.
-func decodeFunction(x js.Value, v reflect.Value) error {
+func decodeFunction(d *decodeState, x js.Value, v reflect.Value) error {
+	if x.Type() != js.TypeFunction {
+		return InvalidTypeError{x.Type(), v.Type()}
+	}
+
 	funcType := v.Type()
 	outCount := funcType.NumOut()
 
@@ -346,11 +907,10 @@ func decodeFunction(x js.Value, v reflect.Value) error {
 			return []reflect.Value{}
 		}
 
-		returnPtr := reflect.New(funcType.Out(0)).Interface()
-		err := FromJSValue(jsReturn, returnPtr)
-
-		returnVal := reflect.ValueOf(returnPtr).Elem()
+		returnVal := reflect.New(funcType.Out(0)).Elem()
+		err := decodeValue(&decodeState{opts: d.opts}, jsReturn, returnVal)
 		if err != nil {
+			err = newDecodeError(err, jsReturn, funcType.Out(0))
 			if outCount == 1 {
 				panic("error decoding JS return value: " + err.Error())
 			}
@@ -362,7 +922,7 @@ func decodeFunction(x js.Value, v reflect.Value) error {
 		case 1:
 			return []reflect.Value{returnVal}
 		case 2:
-			return []reflect.Value{returnVal, reflect.Zero(v.Type())}
+			return []reflect.Value{returnVal, reflect.Zero(errorType)}
 		default:
 			panic("unexpected amount of return values")
 		}
@@ -370,85 +930,134 @@ func decodeFunction(x js.Value, v reflect.Value) error {
 	return nil
 }
 
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
 // createInterface creates a representation of the provided js.Value.
-func createInterface(x js.Value) interface{} {
+func createInterface(d *decodeState, x js.Value) (interface{}, error) {
+	if isBigInt(x) {
+		return createBigInt(x), nil
+	}
+
 	switch x.Type() {
 	case js.TypeUndefined, js.TypeNull:
-		return nil
+		return nil, nil
 	case js.TypeBoolean:
-		return x.Bool()
+		return x.Bool(), nil
 	case js.TypeNumber:
-		return x.Float()
+		return x.Float(), nil
 	case js.TypeString:
-		return x.String()
+		return x.String(), nil
 	case js.TypeSymbol:
-		// We can't convert it to a Go value in a meaningful way.
-		return x
+		return Symbol{x}, nil
 	case js.TypeObject:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+
 		if isArray(x) {
-			return createArray(x)
+			return createArray(d, x)
 		}
-		return createObject(x)
+		if isTypedArray(x) {
+			return createTypedArray(d, x)
+		}
+		if isJSMap(x) {
+			return createJSMap(d, x)
+		}
+		return createObject(d, x)
 	case js.TypeFunction:
 		var a func(...interface{}) (interface{}, error)
-		err := FromJSValue(x, &a)
+		err := decodeValue(d, x, reflect.ValueOf(&a).Elem())
 		if err != nil {
 			panic("error creating function: " + err.Error())
 		}
-		return a
+		return a, nil
 	default:
 		panic("unknown JS type: " + x.Type().String())
 	}
 }
 
 // createArray creates a slice of interface representing the js.Value.
-func createArray(x js.Value) interface{} {
-	result := make([]interface{}, x.Length())
+func createArray(d *decodeState, x js.Value) (interface{}, error) {
+	length := x.Length()
+	if err := d.checkLength(length); err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, length)
 	for i := range result {
-		result[i] = createInterface(x.Index(i))
+		elem, err := createInterface(d, x.Index(i))
+		if err != nil {
+			return nil, withIndex(err, i, x.Index(i), interfaceType)
+		}
+		result[i] = elem
 	}
-	return result
+	return result, nil
 }
 
 // createObject creates a representation of the provided JS object.
-func createObject(x js.Value) interface{} {
-	// TODO: Use Object API
-	obj, err := Global().Get("Object")
-	if err != nil {
-		panic("Object not found")
+func createObject(d *decodeState, x js.Value) (interface{}, error) {
+	keys := objectKeys(x)
+	result := make(map[string]interface{}, len(keys))
+	for _, v := range keys {
+		value, err := createInterface(d, x.Get(v))
+		if err != nil {
+			return nil, withProperty(err, v, x.Get(v), interfaceType)
+		}
+		result[v] = value
 	}
+	return result, nil
+}
 
-	var keys []string
-	err = FromJSValue(obj.Call("keys", x), &keys)
-	if err != nil {
-		panic("Object.keys returned non-string-array.")
+// createJSMap creates a map[interface{}]interface{} representing the provided JS Map.
+// It returns an InvalidMapKeyError if a key of the JS Map cannot be represented as a comparable Go value, such as an
+// object.
+func createJSMap(d *decodeState, x js.Value) (interface{}, error) {
+	entries := globalConstructor("Array").Call("from", x)
+	result := make(map[interface{}]interface{}, entries.Length())
+	for i := 0; i < entries.Length(); i++ {
+		entry := entries.Index(i)
+		key, err := createInterface(d, entry.Index(0))
+		if err != nil {
+			return nil, withEntry(err, i, "key", entry.Index(0), interfaceType)
+		}
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, withEntry(InvalidMapKeyError{reflect.TypeOf(key)}, i, "key", entry.Index(0), interfaceType)
+		}
+
+		value, err := createInterface(d, entry.Index(1))
+		if err != nil {
+			return nil, withEntry(err, i, "value", entry.Index(1), interfaceType)
+		}
+		result[key] = value
 	}
+	return result, nil
+}
 
-	result := make(map[string]interface{}, len(keys))
-	for _, v := range keys {
-		result[v] = createInterface(x.Get(v))
+// objectKeys calls the JS function Object.keys to get the names of the own enumerable properties of the provided
+// js.Value.
+func objectKeys(x js.Value) []string {
+	jsKeys := globalConstructor("Object").Call("keys", x)
+	keys := make([]string, jsKeys.Length())
+	for i := range keys {
+		keys[i] = jsKeys.Index(i).String()
 	}
-	return result
+	return keys
 }
 
 // isArray calls the JS function Array.isArray to check if the provided js.Value is an array.
 func isArray(x js.Value) bool {
-	arr, err := Global().Get("Array")
-	if err != nil {
-		panic("Array not found")
-	}
+	return globalConstructor("Array").Call("isArray", x).Bool()
+}
 
-	return arr.Call("isArray", x).Bool()
+// isJSMap uses x instanceof Map to check if the provided js.Value is a JS Map.
+func isJSMap(x js.Value) bool {
+	return x.InstanceOf(globalConstructor("Map"))
 }
 
 // isDate uses x instanceof Date to check if the provided js.Value is a Date.
 func isDate(x js.Value) bool {
-	date, err := Global().Get("Date")
-	if err != nil {
-		panic("Date not found")
-	}
-
-	return x.InstanceOf(date)
+	return x.InstanceOf(globalConstructor("Date"))
 }
 
 // initializePointerIfNil checks if the pointer is nil and initializes it as necessary.
@@ -0,0 +1,168 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+// evalJS evaluates the provided JS expression.
+func evalJS(expr string) js.Value {
+	return js.Global().Get("Function").New("return " + expr).Invoke()
+}
+
+func TestDecodeJSMapInterface(t *testing.T) {
+	var got interface{}
+	if err := FromJSValue(evalJS(`new Map([[1, "one"], ["two", 2]])`), &got); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	want := map[interface{}]interface{}{1.0: "one", "two": 2.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromJSValue = %#v, want %#v", got, want)
+	}
+
+	err := FromJSValue(evalJS(`new Map([[1, "one"], [{}, "object"]])`), &got)
+	var keyErr InvalidMapKeyError
+	if !errors.As(err, &keyErr) {
+		t.Errorf("FromJSValue with an object key returned %v, want an InvalidMapKeyError", err)
+	}
+}
+
+func TestDecodeJSMapUnhashableKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		into interface{}
+	}{
+		{"object key", `new Map([[1, "one"], [{}, "object"]])`, new(map[interface{}]string)},
+		{"array key", `new Map([[[1, 2], "array"]])`, new(map[interface{}]interface{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromJSValue(evalJS(tt.expr), tt.into)
+			var keyErr InvalidMapKeyError
+			if !errors.As(err, &keyErr) {
+				t.Errorf("FromJSValue returned %v, want an InvalidMapKeyError", err)
+			}
+		})
+	}
+
+	var got map[interface{}]string
+	if err := FromJSValue(evalJS(`new Map([[1, "one"], ["two", "2"]])`), &got); err != nil {
+		t.Fatalf("FromJSValue with hashable keys returned error: %v", err)
+	}
+	if want := map[interface{}]string{1.0: "one", "two": "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromJSValue = %#v, want %#v", got, want)
+	}
+}
+
+type knownFieldsRect struct {
+	Width, Height float64
+}
+
+func (r knownFieldsRect) Area() float64 {
+	return r.Width * r.Height
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	area := namingPolicy(reflect.TypeOf(knownFieldsRect{})).name("Area")
+	dec := NewDecoder(DecoderOptions{DisallowUnknownFields: true})
+
+	tests := []struct {
+		name    string
+		expr    string
+		unknown string
+	}{
+		{"fields", `{Width: 2, Height: 3}`, ""},
+		{"unknown property", `{Width: 2, Depth: 3}`, "Depth"},
+		{"method", `{Width: 2, ` + area + `: 6}`, area},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r knownFieldsRect
+			err := dec.FromJSValue(evalJS(tt.expr), &r)
+
+			var unknownErr UnknownFieldError
+			switch {
+			case tt.unknown == "" && err != nil:
+				t.Errorf("FromJSValue returned error: %v", err)
+			case tt.unknown != "" && (!errors.As(err, &unknownErr) || unknownErr.Property != tt.unknown):
+				t.Errorf("FromJSValue returned %v, want an UnknownFieldError for %q", err, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    DecoderOptions
+		expr    string
+		into    interface{}
+		wantErr interface{}
+	}{
+		{"depth at limit", DecoderOptions{MaxDepth: 2}, `{a: [1]}`, new(map[string][]int), nil},
+		{"depth above limit", DecoderOptions{MaxDepth: 2}, `{a: [[1]]}`, new(map[string][][]int), new(DepthError)},
+		{"interface depth at limit", DecoderOptions{MaxDepth: 2}, `[{}]`, new(interface{}), nil},
+		{"interface depth above limit", DecoderOptions{MaxDepth: 2}, `[{a: {}}]`, new(interface{}), new(DepthError)},
+		{"struct depth above limit", DecoderOptions{MaxDepth: 1}, `{A: {}}`, new(struct{ A struct{} }), new(DepthError)},
+		{"length at limit", DecoderOptions{MaxArrayLength: 3}, `[1, 2, 3]`, new([]int), nil},
+		{"length above limit", DecoderOptions{MaxArrayLength: 3}, `[1, 2, 3, 4]`, new([]int), new(ArrayLengthError)},
+		{"interface length above limit", DecoderOptions{MaxArrayLength: 3}, `[1, 2, 3, 4]`, new(interface{}), new(ArrayLengthError)},
+		{"TypedArray length at limit", DecoderOptions{MaxArrayLength: 3}, `new Int16Array(3)`, new([]int16), nil},
+		{"TypedArray length above limit", DecoderOptions{MaxArrayLength: 3}, `new Int16Array(4)`, new([]int16), new(ArrayLengthError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDecoder(tt.opts).FromJSValue(evalJS(tt.expr), tt.into)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("FromJSValue(%s) returned error: %v", tt.expr, err)
+			case tt.wantErr != nil && !errors.As(err, tt.wantErr):
+				t.Errorf("FromJSValue(%s) returned %v, want a %T", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestZeroUndefined(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+
+	tests := []struct {
+		name string
+		opts DecoderOptions
+		want point
+	}{
+		{"keep", DecoderOptions{}, point{X: 1, Y: 2}},
+		{"zero", DecoderOptions{ZeroUndefined: true}, point{X: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(tt.opts)
+
+			p := point{X: 5, Y: 2}
+			if err := dec.FromJSValue(evalJS(`{X: 1, Y: undefined}`), &p); err != nil {
+				t.Fatalf("FromJSValue into a struct returned error: %v", err)
+			}
+			if p != tt.want {
+				t.Errorf("FromJSValue into a struct = %+v, want %+v", p, tt.want)
+			}
+
+			// Maps are replaced as a whole, so undefined values become zero values either way.
+			m := map[string]int{"x": 5, "y": 2}
+			if err := dec.FromJSValue(evalJS(`{x: 1, y: undefined}`), &m); err != nil {
+				t.Fatalf("FromJSValue into a map returned error: %v", err)
+			}
+			if want := map[string]int{"x": 1, "y": 0}; !reflect.DeepEqual(m, want) {
+				t.Errorf("FromJSValue into a map = %v, want %v", m, want)
+			}
+		})
+	}
+}
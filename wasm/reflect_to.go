@@ -1,8 +1,11 @@
 package wasm
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
+	"strconv"
+	"sync"
 	"syscall/js"
 	"time"
 	"unsafe"
@@ -14,8 +17,17 @@ type Wrapper interface {
 	JSValue() js.Value
 }
 
+var (
+	wrapperType = reflect.TypeOf((*Wrapper)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
 // ToJSValue converts a given Go value into its equivalent JS form.
 //
+// Types that do not implement Wrapper fall back to encoding.TextMarshaler, which is converted into a string, and then to
+// json.Marshaler, which is converted into the result of JSON.parse. If only the pointer type implements them, they are
+// used when the value is addressable.
+//
 // One special case is that complex numbers (complex64 and complex128) are converted into objects with a real and imag
 // property holding a number each.
 //
@@ -28,21 +40,124 @@ type Wrapper interface {
 // If the last return value of a function is an error, it will be thrown in JS if it's non-nil.
 // If the function returns multiple non-error values, it is converted to an array when returning to JS.
 //
-// It panics when a channel or a map with keys other than string and integers are passed in.
+// Slices and arrays of 8, 16 and 32-bit integers and floats are converted into the TypedArray with the same element
+// type by copying their memory in bulk. Notably, []byte is converted into a Uint8Array.
+//
+// Exported struct fields are converted into properties of a JS object. The wasm struct tag overrides the name of the
+// property, or skips the field entirely when it is "-". It also accepts the omitempty and string options, which behave
+// like their encoding/json counterparts. The jsmap option converts a map field to a JS Map and the bigint option
+// converts a 64-bit integer or big.Int field to a JS BigInt. The fields of embedded structs without a tag name are
+// promoted into the parent object.
+//
+// Numbers are converted into JS numbers, which cannot hold integers larger than 2^53 precisely. An Encoder with the
+// BigInts option converts int, uint, int64 and uint64, which are all 64-bit on WebAssembly, into JS BigInts instead.
+// A big.Int is converted into a string, or into a BigInt with the BigInts option.
+//
+// Maps are converted into JS objects, which requires their keys to be strings, integers or to implement
+// encoding.TextMarshaler. An Encoder with the JSMaps option converts them into JS Map instances instead.
+//
+// Interfaces are converted from their concrete value. For interfaces registered with RegisterVariants, the
+// discriminator property of the concrete type is set on the resulting JS object.
+//
+// Pointers, maps and slices that refer back to themselves make it panic with a CycleError, which Encode returns
+// instead. An Encoder with the
+// References option converts every occurrence of the same pointer or map to the same JS value instead, which keeps
+// aliasing and cycles intact; cycles going through a slice still result in a CycleError.
+//
+// Channels that can be received from are converted into JS async iterables, which can be consumed with a for await
+// loop. A value is received from the channel every time JS asks for the next one and the iteration ends when the
+// channel is closed. Breaking out of the loop stops receiving from the channel.
+//
+// It panics when a send-only channel or a map with unsupported keys are passed in.
 func ToJSValue(x interface{}) js.Value {
+	return defaultEncoder.ToJSValue(x)
+}
+
+// Encode converts a given Go value into its equivalent JS form like ToJSValue, but returns a CycleError instead of
+// panicking with it.
+func Encode(x interface{}) (js.Value, error) {
+	return defaultEncoder.Encode(x)
+}
+
+// EncoderOptions configures how an Encoder converts Go values to JS.
+type EncoderOptions struct {
+	// JSMaps converts Go maps to JS Map instances instead of plain objects, so that keys other than strings keep their
+	// identity. A map field can also opt in individually with the jsmap option of the wasm tag.
+	JSMaps bool
+
+	// BigInts converts int, uint, int64, uint64 and big.Int values to JS BigInts so that they keep their precision, as
+	// int and uint are 64-bit on WebAssembly too. A field can also opt in individually with the bigint option of the
+	// wasm tag.
+	BigInts bool
+
+	// Arguments configures how Go functions converted by the Encoder decode the arguments they receive from JS.
+	// Encoder.Expose and Encoder.ExposeAsync expose functions that use them.
+	Arguments DecoderOptions
+
+	// References converts every occurrence of the same Go pointer or map to the same JS value, preserving aliasing
+	// and allowing values with cycles, such as a tree whose children point back to their parent.
+	References bool
+}
+
+// Encoder converts Go values to JS according to its options.
+type Encoder struct {
+	opts EncoderOptions
+}
+
+// defaultEncoder is the Encoder used by ToJSValue.
+var defaultEncoder = NewEncoder(EncoderOptions{})
+
+// NewEncoder returns an Encoder with the provided options.
+func NewEncoder(opts EncoderOptions) *Encoder {
+	return &Encoder{opts}
+}
+
+// ToJSValue converts a given Go value into its equivalent JS form, as described by the package-level ToJSValue.
+func (enc *Encoder) ToJSValue(x interface{}) js.Value {
+	e := &encodeState{opts: enc.opts}
+	return e.encode(x)
+}
+
+// Encode converts a given Go value into its equivalent JS form like ToJSValue, but returns a CycleError instead of
+// panicking with it.
+func (enc *Encoder) Encode(x interface{}) (result js.Value, err error) {
+	defer recoverCycle(&err)
+	return enc.ToJSValue(x), nil
+}
+
+// encodeState holds the state of a single conversion from Go to JS.
+type encodeState struct {
+	opts EncoderOptions
+
+	// Depth of the pointers, maps and slices being converted, and the ones seen past startDetectingCyclesAfter.
+	ptrLevel uint
+	ptrSeen  map[refKey]struct{}
+
+	// JS values of the pointers and maps converted so far with the References option, and the ones waiting for the
+	// next JS object to be created.
+	refs        map[refKey]js.Value
+	pendingRefs []refKey
+}
+
+// encode converts a given Go value into its equivalent JS form.
+func (e *encodeState) encode(x interface{}) js.Value {
 	if x == nil {
 		return js.Null()
 	}
 
 	// Fast path for basic types that do not require reflection.
 	switch x := x.(type) {
+	case asyncFunc:
+		return toAsyncJSFunc(x.fn, e.opts).JSValue()
 	case Wrapper:
 		return x.JSValue()
 	case js.Value:
 		return x
-	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+	case bool, int8, int16, int32, uint8, uint16, uint32, uintptr,
 		unsafe.Pointer, float32, float64, string:
 		return js.ValueOf(x)
+	case []byte:
+		return bytesToJS(x)
 	case complex64:
 		return js.ValueOf(map[string]interface{}{
 			"real": real(x),
@@ -54,148 +169,414 @@ func ToJSValue(x interface{}) js.Value {
 			"imag": imag(x),
 		})
 	case time.Time:
-		date, err := Global().Get("Date")
-		if err != nil {
-			panic("Date constructor not found")
-		}
-		return date.New(x.Format(time.RFC3339))
+		return timeToJS(x)
 	}
 
-	value := reflect.ValueOf(x)
+	return typeEncoder(reflect.TypeOf(x))(e, reflect.ValueOf(x))
+}
 
-	if value.Kind() == reflect.Ptr {
-		value = reflect.Indirect(value)
-		if !value.IsValid() {
-			return js.Undefined()
-		}
+// encoderFunc converts a reflect.Value of a specific type to its JS equivalent.
+type encoderFunc func(e *encodeState, v reflect.Value) js.Value
+
+// encoderCache holds the compiled encoderFunc of every type converted so far.
+var encoderCache sync.Map // map[reflect.Type]encoderFunc
+
+// typeEncoder returns the cached encoderFunc of the provided type, compiling it if necessary.
+func typeEncoder(t reflect.Type) encoderFunc {
+	if fi, ok := encoderCache.Load(t); ok {
+		return fi.(encoderFunc)
 	}
 
-	switch k := value.Kind(); k {
+	// To deal with recursive types, populate the cache with an indirect func before compiling the encoder.
+	// It waits on the real func to be ready before using it.
+	var (
+		wg sync.WaitGroup
+		f  encoderFunc
+	)
+	wg.Add(1)
+	fi, loaded := encoderCache.LoadOrStore(t, encoderFunc(func(e *encodeState, v reflect.Value) js.Value {
+		wg.Wait()
+		return f(e, v)
+	}))
+	if loaded {
+		return fi.(encoderFunc)
+	}
+
+	f = newTypeEncoder(t)
+	wg.Done()
+	encoderCache.Store(t, f)
+	return f
+}
+
+// newTypeEncoder compiles the encoderFunc of the provided type.
+func newTypeEncoder(t reflect.Type) encoderFunc {
+	if t.Implements(wrapperType) {
+		return wrapperEncoder
+	}
+
+	switch t {
+	case jsValueType:
+		return jsValueEncoder
+	case timeType:
+		return timeEncoder
+	case bigIntType, reflect.PtrTo(bigIntType):
+		return bigIntEncoder
+	}
+
+	kindEncoder := func() encoderFunc {
+		return newKindEncoder(t)
+	}
+	if enc := newMarshalerEncoder(t, kindEncoder); enc != nil {
+		return enc
+	}
+	return kindEncoder()
+}
+
+// newKindEncoder compiles the encoderFunc of the provided type based on its kind.
+func newKindEncoder(t reflect.Type) encoderFunc {
+	switch t.Kind() {
 	case reflect.Bool:
-		return js.ValueOf(value.Bool())
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return js.ValueOf(value.Int())
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return js.ValueOf(value.Uint())
+		return boolEncoder
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return intEncoder
+	case reflect.Int, reflect.Int64:
+		// int is 64-bit on WebAssembly.
+		return int64Encoder
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return uintEncoder
+	case reflect.Uint, reflect.Uint64:
+		return uint64Encoder
 	case reflect.Uintptr:
-		return js.ValueOf(value.Pointer())
+		return uintptrEncoder
 	case reflect.Float32, reflect.Float64:
-		return js.ValueOf(value.Float())
+		return floatEncoder
+	case reflect.Complex64, reflect.Complex128:
+		return complexEncoder
 	case reflect.String:
-		return js.ValueOf(value.String())
+		return stringEncoder
+	case reflect.Interface:
+		if vs, ok := typeVariants(t); ok {
+			return newVariantEncoder(vs)
+		}
+		return interfaceEncoder
+	case reflect.Ptr:
+		return newPtrEncoder(t)
 	case reflect.Array, reflect.Slice:
-		return toJSArray(value)
+		if name, ok := typedArrayName(t); ok {
+			return newTypedArrayEncoder(name)
+		}
+		return newArrayEncoder(t)
 	case reflect.Func:
-		return toJSFunc(value)
+		return funcEncoder
 	case reflect.Map:
-		return mapToJSObject(value)
+		return newMapEncoder(t)
 	case reflect.Struct:
-		return structToJSObject(value)
+		return newStructEncoder(t)
+	case reflect.Chan:
+		return newChanEncoder(t)
 	default:
-		panic(fmt.Sprintf("cannot convert %T to a JS value (kind %s)", x, k))
-	}
-}
-
-// toJSArray converts the provided array or slice to a JS array.
-func toJSArray(x reflect.Value) js.Value {
-	arrayConstructor, err := Global().Get("Array")
-	if err != nil {
-		panic("Array constructor not found")
-	}
-
-	array := arrayConstructor.New()
-	for i := 0; i < x.Len(); i++ {
-		array.SetIndex(i, ToJSValue(x.Index(i).Interface()))
-	}
-
-	return array
-}
-
-// mapToJSObject converts the provided map to a JS object.
-func mapToJSObject(x reflect.Value) js.Value {
-	objectConstructor, err := Global().Get("Object")
-	if err != nil {
-		panic("Object constructor not found")
-	}
-
-	obj := objectConstructor.New()
-	iter := x.MapRange()
-	for iter.Next() {
-		key := iter.Key()
-		value := iter.Value().Interface()
-		switch key := key.Interface().(type) {
-		case int:
-			obj.SetIndex(key, ToJSValue(value))
-		case int8:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case int16:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case int32:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case int64:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uint:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uint8:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uint16:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uint32:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uint64:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case uintptr:
-			obj.SetIndex(int(key), ToJSValue(value))
-		case string:
-			obj.Set(key, ToJSValue(value))
-		default:
-			panic(fmt.Sprintf("cannot convert %T into a JS value as its key is not a string or an integer",
-				x.Interface()))
+		return unsupportedTypeEncoder
+	}
+}
+
+func wrapperEncoder(e *encodeState, v reflect.Value) js.Value {
+	switch {
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		return js.Undefined()
+	case v.Kind() == reflect.Interface && v.IsNil():
+		// Interface types implementing Wrapper use this encoder as well.
+		return js.Null()
+	}
+
+	w := v.Interface().(Wrapper)
+	if a, ok := w.(asyncFunc); ok {
+		// Unlike other Wrappers, functions marked with Async are converted with the options of the Encoder.
+		return toAsyncJSFunc(a.fn, e.opts).JSValue()
+	}
+	return w.JSValue()
+}
+
+func jsValueEncoder(e *encodeState, v reflect.Value) js.Value {
+	return v.Interface().(js.Value)
+}
+
+func timeEncoder(e *encodeState, v reflect.Value) js.Value {
+	return timeToJS(v.Interface().(time.Time))
+}
+
+func boolEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.Bool())
+}
+
+func intEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.Int())
+}
+
+func uintEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.Uint())
+}
+
+func uintptrEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.Uint())
+}
+
+func floatEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.Float())
+}
+
+func complexEncoder(e *encodeState, v reflect.Value) js.Value {
+	c := v.Complex()
+	return js.ValueOf(map[string]interface{}{
+		"real": real(c),
+		"imag": imag(c),
+	})
+}
+
+func stringEncoder(e *encodeState, v reflect.Value) js.Value {
+	return js.ValueOf(v.String())
+}
+
+func interfaceEncoder(e *encodeState, v reflect.Value) js.Value {
+	if v.IsNil() {
+		return js.Null()
+	}
+	return e.encode(v.Elem().Interface())
+}
+
+func funcEncoder(e *encodeState, v reflect.Value) js.Value {
+	return toJSFunc(v, e.opts).JSValue()
+}
+
+func unsupportedTypeEncoder(e *encodeState, v reflect.Value) js.Value {
+	panic(fmt.Sprintf("cannot convert %s to a JS value (kind %s)", v.Type(), v.Kind()))
+}
+
+// newPtrEncoder returns an encoderFunc that unwraps the pointer, converting nil pointers to undefined.
+func newPtrEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	derefEnc := func(e *encodeState, v reflect.Value) js.Value {
+		return elemEnc(e, v.Elem())
+	}
+	return func(e *encodeState, v reflect.Value) js.Value {
+		if v.IsNil() {
+			return js.Undefined()
 		}
+		return e.encodeRef(v, derefEnc)
 	}
+}
 
-	return obj
+// newTypedArrayEncoder returns an encoderFunc that converts a slice or an array of numbers to the named TypedArray.
+func newTypedArrayEncoder(name string) encoderFunc {
+	return func(e *encodeState, v reflect.Value) js.Value {
+		return toJSTypedArray(v, name)
+	}
 }
 
-// structToJSObject converts a struct to a JS object.
-func structToJSObject(x reflect.Value) js.Value {
-	objectConstructor, err := Global().Get("Object")
-	if err != nil {
-		panic("Object constructor not found")
+// newArrayEncoder returns an encoderFunc that converts a slice or an array to a JS array.
+func newArrayEncoder(t reflect.Type) encoderFunc {
+	elemEnc := typeEncoder(t.Elem())
+	arrayEnc := func(e *encodeState, v reflect.Value) js.Value {
+		array := globalConstructor("Array").New(v.Len())
+		e.addRef(array)
+		for i := 0; i < v.Len(); i++ {
+			array.SetIndex(i, elemEnc(e, v.Index(i)))
+		}
+		return array
+	}
+	if t.Kind() == reflect.Array {
+		return arrayEnc
 	}
 
-	obj := objectConstructor.New()
+	return func(e *encodeState, v reflect.Value) js.Value {
+		key := newRefKey(v)
+		e.enter(key)
+		defer e.leave(key)
+		return arrayEnc(e, v)
+	}
+}
 
-	structType := x.Type()
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		if field.PkgPath != "" {
-			continue
+// newMapEncoder returns an encoderFunc that converts a map to a JS object, or to a JS Map if the JSMaps option is set.
+func newMapEncoder(t reflect.Type) encoderFunc {
+	objectEnc := newObjectMapEncoder(t)
+	jsMapEnc := newJSMapEncoder(t)
+	return func(e *encodeState, v reflect.Value) js.Value {
+		if e.opts.JSMaps {
+			return jsMapEnc(e, v)
 		}
+		return objectEnc(e, v)
+	}
+}
 
-		name := field.Name
-		if tagName, ok := field.Tag.Lookup("wasm"); ok {
-			if tagName == "-" {
-				continue
+// newObjectMapEncoder returns an encoderFunc that converts a map to a JS object.
+// The keys of the map must be strings, integers or implement encoding.TextMarshaler.
+func newObjectMapEncoder(t reflect.Type) encoderFunc {
+	keyType := t.Key()
+	switch keyType.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !keyType.Implements(textMarshalerType) {
+			return func(e *encodeState, v reflect.Value) js.Value {
+				panic(fmt.Sprintf("cannot convert %s into a JS object as its key is not a string or an integer", v.Type()))
 			}
-			name = tagName
 		}
+	}
 
-		obj.Set(name, ToJSValue(x.Field(i).Interface()))
+	elemEnc := typeEncoder(t.Elem())
+	objectEnc := func(e *encodeState, v reflect.Value) js.Value {
+		obj := globalConstructor("Object").New()
+		e.addRef(obj)
+		iter := v.MapRange()
+		for iter.Next() {
+			obj.Set(resolveKeyName(iter.Key()), elemEnc(e, iter.Value()))
+		}
+		return obj
+	}
+	return func(e *encodeState, v reflect.Value) js.Value {
+		return e.encodeRef(v, objectEnc)
 	}
+}
 
-	for i := 0; i < structType.NumMethod(); i++ {
-		method := structType.Method(i)
-		obj.Set(method.Name, toJSFunc(x.Method(i)))
+// resolveKeyName converts a map key to the name of a JS property.
+// Integers are formatted in full, which SetIndex would truncate when larger than an int.
+func resolveKeyName(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
 	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return ""
+		}
+		text, err := tm.MarshalText()
+		if err != nil {
+			panic(fmt.Sprintf("error marshalling map key of type %s to text: %v", k.Type(), err))
+		}
+		return string(text)
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	default:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+}
 
-	if x.CanAddr() {
-		structPtr := reflect.PointerTo(structType)
-		for i := 0; i < structPtr.NumMethod(); i++ {
-			method := structPtr.Method(i)
-			obj.Set(method.Name, toJSFunc(x.Addr().Method(i)))
+// newJSMapEncoder returns an encoderFunc that converts a map to a JS Map, converting keys like any other value.
+func newJSMapEncoder(t reflect.Type) encoderFunc {
+	keyEnc := typeEncoder(t.Key())
+	elemEnc := typeEncoder(t.Elem())
+	jsMapEnc := func(e *encodeState, v reflect.Value) js.Value {
+		m := globalConstructor("Map").New()
+		e.addRef(m)
+		iter := v.MapRange()
+		for iter.Next() {
+			m.Call("set", keyEnc(e, iter.Key()), elemEnc(e, iter.Value()))
 		}
+		return m
+	}
+	return func(e *encodeState, v reflect.Value) js.Value {
+		return e.encodeRef(v, jsMapEnc)
+	}
+}
+
+// structEncoder converts a struct to a JS object using the fields and methods of its type computed ahead of time.
+type structEncoder struct {
+	fields    []field
+	fieldEncs []encoderFunc
+	methods   []structMethod
+	accessors []structAccessor
+}
+
+// newStructEncoder returns an encoderFunc that converts a struct to a JS object.
+// Its exported methods are converted to functions or accessor properties according to its MethodOptions, including the
+// pointer ones if the struct is addressable.
+func newStructEncoder(t reflect.Type) encoderFunc {
+	return compileStructEncoder(t).encode
+}
+
+// compileStructEncoder computes the fields and methods of the provided struct type and their encoders.
+func compileStructEncoder(t reflect.Type) structEncoder {
+	se := structEncoder{
+		fields: typeFields(t),
+	}
+	for _, f := range se.fields {
+		if f.jsMap && f.typ.Kind() == reflect.Map {
+			se.fieldEncs = append(se.fieldEncs, newJSMapEncoder(f.typ))
+			continue
+		}
+		if f.bigInt {
+			se.fieldEncs = append(se.fieldEncs, forcedBigIntEncoder)
+			continue
+		}
+		se.fieldEncs = append(se.fieldEncs, typeEncoder(f.typ))
+	}
+	se.methods, se.accessors = typeMethods(t)
+	return se
+}
+
+func (se structEncoder) encode(e *encodeState, x reflect.Value) js.Value {
+	obj := globalConstructor("Object").New()
+	e.addRef(obj)
+
+	for i, f := range se.fields {
+		fv, _ := fieldByIndex(x, f.index, false)
+		if !fv.IsValid() {
+			// The field is promoted from a nil embedded pointer.
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		obj.Set(f.name, se.encodeField(e, i, fv))
+	}
+
+	for _, m := range se.methods {
+		if fn, ok := m.method(x); ok {
+			obj.Set(m.name, toJSFunc(fn, e.opts).JSValue())
+		}
+	}
+
+	for _, a := range se.accessors {
+		a.define(e, obj, x)
 	}
 
 	return obj
 }
+
+// encodeField converts the value of the i-th field, honoring the options of its wasm tag.
+func (se structEncoder) encodeField(e *encodeState, i int, fv reflect.Value) js.Value {
+	if se.fields[i].quoted {
+		return quoteValue(e, fv)
+	}
+	return se.fieldEncs[i](e, fv)
+}
+
+// timeToJS converts the provided time to a JS Date.
+func timeToJS(t time.Time) js.Value {
+	return globalConstructor("Date").New(t.Format(time.RFC3339))
+}
+
+// quoteValue converts a boolean or a number to a JS string, as requested by the string option of the wasm tag.
+// Nil pointers are converted as usual.
+func quoteValue(e *encodeState, x reflect.Value) js.Value {
+	if x.Kind() == reflect.Ptr {
+		if x.IsNil() {
+			return e.encode(x.Interface())
+		}
+		x = x.Elem()
+	}
+
+	switch x.Kind() {
+	case reflect.Bool:
+		return js.ValueOf(strconv.FormatBool(x.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return js.ValueOf(strconv.FormatInt(x.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return js.ValueOf(strconv.FormatUint(x.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return js.ValueOf(strconv.FormatFloat(x.Float(), 'g', -1, x.Type().Bits()))
+	default:
+		return e.encode(x.Interface())
+	}
+}
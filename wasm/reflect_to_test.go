@@ -0,0 +1,162 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	type inner struct {
+		N int
+	}
+
+	tests := []struct {
+		name string
+		in   interface{}
+	}{
+		{"bool", true},
+		{"int", -42},
+		{"uint", uint(42)},
+		{"float", 4.5},
+		{"string", "Team Ortix"},
+		{"slice", []string{"a", "b"}},
+		{"array", [2]int{1, 2}},
+		{"map", map[string]int{"a": 1}},
+		{"int keys", map[int]string{1: "a"}},
+		{"struct", inner{N: 3}},
+		{"pointer", &inner{N: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := defaultEncoder.Encode(tt.in)
+			if err != nil {
+				t.Fatalf("Encode(%#v) returned error: %v", tt.in, err)
+			}
+
+			out := reflect.New(reflect.TypeOf(tt.in))
+			if err := FromJSValue(x, out.Interface()); err != nil {
+				t.Fatalf("FromJSValue(%v) returned error: %v", x, err)
+			}
+			if !reflect.DeepEqual(out.Elem().Interface(), tt.in) {
+				t.Errorf("round trip of %#v = %#v", tt.in, out.Elem().Interface())
+			}
+		})
+	}
+}
+
+func TestEncodeUintptr(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		get  func(js.Value) js.Value
+	}{
+		{"value", uintptr(3), func(x js.Value) js.Value { return x }},
+		{"field", struct{ P uintptr }{3}, func(x js.Value) js.Value { return x.Get("P") }},
+		{"element", []uintptr{3}, func(x js.Value) js.Value { return x.Index(0) }},
+		{"map value", map[string]uintptr{"p": 3}, func(x js.Value) js.Value { return x.Get("p") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := defaultEncoder.Encode(tt.in)
+			if err != nil {
+				t.Fatalf("Encode(%#v) returned error: %v", tt.in, err)
+			}
+			if got := tt.get(x); got.Type() != js.TypeNumber || got.Int() != 3 {
+				t.Errorf("Encode(%#v) = %v, want 3", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestEncoderFunctionOptions(t *testing.T) {
+	enc := NewEncoder(EncoderOptions{Arguments: DecoderOptions{StrictNumbers: true}})
+	identity := func(n int) int { return n }
+
+	if err := enc.Expose("strictIdentity", identity); err != nil {
+		t.Fatalf("Expose returned error: %v", err)
+	}
+	if err := enc.ExposeAsync("strictIdentityAsync", identity); err != nil {
+		t.Fatalf("ExposeAsync returned error: %v", err)
+	}
+	defer bridge.Delete("strictIdentity")
+	defer bridge.Delete("strictIdentityAsync")
+
+	t.Run("sync", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("strictIdentity(1.5) did not throw")
+			}
+		}()
+		bridge.value.Call("strictIdentity", 1.5)
+	})
+
+	t.Run("async", func(t *testing.T) {
+		var p Promise
+		if err := FromJSValue(bridge.value.Call("strictIdentityAsync", 1.5), &p); err != nil {
+			t.Fatalf("FromJSValue returned error: %v", err)
+		}
+		var n int
+		if err := p.Await(&n); err == nil {
+			t.Errorf("strictIdentityAsync(1.5) resolved to %d", n)
+		}
+	})
+}
+
+func TestCycleError(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	cyclic := &node{}
+	cyclic.Next = cyclic
+
+	if _, err := Encode(cyclic); !errors.As(err, new(CycleError)) {
+		t.Errorf("Encode returned %v, want a CycleError", err)
+	}
+	if err := Expose("cyclic", cyclic); !errors.As(err, new(CycleError)) {
+		t.Errorf("Expose returned %v, want a CycleError", err)
+	}
+	if x, _ := bridge.Get("cyclic"); !x.IsUndefined() {
+		t.Errorf("Expose exposed %v despite the cycle", x)
+	}
+
+	func() {
+		defer func() {
+			if _, ok := recover().(CycleError); !ok {
+				t.Error("ToJSValue did not panic with a CycleError")
+			}
+		}()
+		ToJSValue(cyclic)
+	}()
+}
+
+func TestEncodeWrappers(t *testing.T) {
+	global := Global()
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want js.Value
+	}{
+		{"nil interface", struct{ W Wrapper }{}, js.Null()},
+		{"nil pointer", struct{ W *Object }{}, js.Undefined()},
+		{"interface", struct{ W Wrapper }{global}, js.Global()},
+		{"pointer", struct{ W *Object }{&global}, js.Global()},
+		{"value", struct{ W Object }{global}, js.Global()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := defaultEncoder.Encode(tt.in)
+			if err != nil {
+				t.Fatalf("Encode(%#v) returned error: %v", tt.in, err)
+			}
+			if got := x.Get("W"); !got.Equal(tt.want) {
+				t.Errorf("Encode(%#v).W = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package wasm
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"syscall/js"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// signalContext returns the context passed to a Go function with a leading context.Context parameter, the JS
+// arguments left to decode and a function that releases the context once the call is over. It returns a nil context
+// if the function takes none.
+//
+// The AbortSignal passed by JS is either the last argument, which is then removed from the arguments, or the signal
+// property of the last argument, such as an options object, which is only removed if the function takes no parameter
+// for it. The context is cancelled if the signal already aborted. For async functions, it is also cancelled when the
+// signal aborts later on. Sync functions block the JS event loop until they return, so the abort event could never
+// be dispatched to them and it is not listened to.
+func signalContext(
+	funcType reflect.Type, args []js.Value, async bool,
+) (context.Context, []js.Value, context.CancelFunc) {
+	if funcType.NumIn() == 0 || funcType.In(0) != contextType {
+		return nil, args, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if len(args) == 0 {
+		return ctx, args, cancel
+	}
+
+	var signal js.Value
+	last := args[len(args)-1]
+	if isAbortSignal(last) {
+		signal = last
+		args = args[:len(args)-1]
+	} else if typ, ok := jsType(last); ok && typ == js.TypeObject && isAbortSignal(last.Get("signal")) {
+		signal = last.Get("signal")
+		if !funcType.IsVariadic() && len(args) > jsParamCount(funcType) {
+			args = args[:len(args)-1]
+		}
+	} else {
+		return ctx, args, cancel
+	}
+
+	if signal.Get("aborted").Bool() {
+		cancel()
+		return ctx, args, cancel
+	}
+	if !async {
+		return ctx, args, cancel
+	}
+
+	onAbort := newFunc(func(this js.Value, args []js.Value) interface{} {
+		cancel()
+		return nil
+	})
+	signal.Call("addEventListener", "abort", onAbort)
+
+	var once sync.Once
+	return ctx, args, func() {
+		once.Do(func() {
+			cancel()
+			signal.Call("removeEventListener", "abort", onAbort)
+			releaseFunc(onAbort)
+		})
+	}
+}
+
+// jsParamCount returns the number of parameters of the provided function type that are passed by JS, leaving out a
+// leading context.Context and the js.Value that receives `this`.
+func jsParamCount(funcType reflect.Type) int {
+	n, first := funcType.NumIn(), 0
+	if n != 0 && funcType.In(0) == contextType {
+		first = 1
+	}
+	if n > first && funcType.In(first) == jsValueType {
+		first++
+	}
+	return n - first
+}
+
+// isAbortSignal checks if the provided js.Value is an AbortSignal.
+func isAbortSignal(x js.Value) bool {
+	abortSignal := js.Global().Get("AbortSignal")
+	if abortSignal.Type() != js.TypeFunction {
+		return false
+	}
+	typ, ok := jsType(x)
+	return ok && typ == js.TypeObject && x.InstanceOf(abortSignal)
+}
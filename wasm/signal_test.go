@@ -0,0 +1,55 @@
+package wasm
+
+import (
+	"context"
+	"syscall/js"
+	"testing"
+	"time"
+)
+
+func TestSignalContext(t *testing.T) {
+	newController := func() js.Value {
+		return globalConstructor("AbortController").New()
+	}
+	ctxErr := func(ctx context.Context) bool {
+		return ctx.Err() != nil
+	}
+
+	t.Run("sync", func(t *testing.T) {
+		fn := ToJSValue(ctxErr)
+
+		controller := newController()
+		if cancelled := fn.Invoke(controller.Get("signal")).Bool(); cancelled {
+			t.Error("context cancelled without the signal aborting")
+		}
+		controller.Call("abort")
+		if cancelled := fn.Invoke(controller.Get("signal")).Bool(); !cancelled {
+			t.Error("context not cancelled with a signal that aborted before the call")
+		}
+		if cancelled := fn.Invoke(map[string]interface{}{"signal": controller.Get("signal")}).Bool(); !cancelled {
+			t.Error("context not cancelled with the signal property of an options object")
+		}
+	})
+
+	t.Run("async", func(t *testing.T) {
+		waitDone := func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+				return context.DeadlineExceeded
+			}
+		}
+		fn := ToJSValue(Async(waitDone))
+
+		controller := newController()
+		var p Promise
+		if err := FromJSValue(fn.Invoke(controller.Get("signal")), &p); err != nil {
+			t.Fatalf("FromJSValue returned error: %v", err)
+		}
+		controller.Call("abort")
+		if err := p.Await(nil); err != nil {
+			t.Errorf("context not cancelled when the signal aborted during the call: %v", err)
+		}
+	})
+}
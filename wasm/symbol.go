@@ -0,0 +1,107 @@
+package wasm
+
+import (
+	"reflect"
+	"syscall/js"
+)
+
+var symbolType = reflect.TypeOf(Symbol{})
+
+// Symbol is an instance of a JS symbol.
+// The zero value of this struct is not a valid Symbol.
+type Symbol struct {
+	value js.Value
+}
+
+// NewSymbol creates a new unique symbol with the provided description.
+// It is implemented by calling Symbol on JS.
+func NewSymbol(description string) Symbol {
+	return Symbol{globalConstructor("Symbol").Invoke(description)}
+}
+
+// SymbolFor returns the symbol registered globally under the provided key, creating it if necessary.
+// It is implemented by calling Symbol.for on JS.
+func SymbolFor(key string) Symbol {
+	return Symbol{globalConstructor("Symbol").Call("for", key)}
+}
+
+// SymbolIterator returns Symbol.iterator, the method used by for...of loops.
+func SymbolIterator() Symbol {
+	return Symbol{wellKnownSymbol("iterator")}
+}
+
+// SymbolAsyncIterator returns Symbol.asyncIterator, the method used by for await...of loops.
+func SymbolAsyncIterator() Symbol {
+	return Symbol{wellKnownSymbol("asyncIterator")}
+}
+
+// SymbolToPrimitive returns Symbol.toPrimitive, the method used to convert an object to a primitive value.
+func SymbolToPrimitive() Symbol {
+	return Symbol{wellKnownSymbol("toPrimitive")}
+}
+
+// FromJSValue turns a JS value to a Symbol.
+// If the js.Value is not a symbol, it returns an InvalidTypeError, or an InvalidBigIntError for a BigInt.
+func (s *Symbol) FromJSValue(value js.Value) error {
+	typ, ok := jsType(value)
+	switch {
+	case !ok:
+		return InvalidBigIntError{bigIntString(value), symbolType}
+	case typ != js.TypeSymbol:
+		return InvalidTypeError{typ, symbolType}
+	}
+
+	s.value = value
+	return nil
+}
+
+// JSValue implements the Wrapper interface.
+func (s Symbol) JSValue() js.Value {
+	return s.value
+}
+
+// Description returns the description of the symbol, or an empty string if it has none.
+// Properties cannot be read from primitives through js.Value.Get, so the symbol is wrapped in an object first.
+func (s Symbol) Description() string {
+	description := globalConstructor("Object").Invoke(s.value).Get("description")
+	if description.Type() != js.TypeString {
+		return ""
+	}
+	return description.String()
+}
+
+// Equal checks if the symbol is the same as another one.
+func (s Symbol) Equal(other Symbol) bool {
+	return s.value.Equal(other.value)
+}
+
+// String returns the description of the symbol in the form Symbol(description) for debugging purposes.
+func (s Symbol) String() string {
+	return "Symbol(" + s.Description() + ")"
+}
+
+// wellKnownSymbol returns the built-in JS symbol with the provided name, such as Symbol.asyncIterator.
+func wellKnownSymbol(name string) js.Value {
+	return globalConstructor("Symbol").Get(name)
+}
+
+// getSymbolProperty returns the property of the provided JS value keyed by a symbol.
+// js.Value.Get only accepts strings, so Reflect.get is used instead.
+func getSymbolProperty(x js.Value, symbol js.Value) js.Value {
+	return js.Global().Get("Reflect").Call("get", x, symbol)
+}
+
+// setSymbolProperty sets the property of the provided JS value keyed by a symbol.
+// js.Value.Set only accepts strings, so Reflect.set is used instead.
+func setSymbolProperty(x js.Value, symbol js.Value, value js.Value) {
+	js.Global().Get("Reflect").Call("set", x, symbol, value)
+}
+
+// defineSymbolProperty defines a non-enumerable property keyed by a symbol on the provided JS object.
+func defineSymbolProperty(x js.Value, symbol js.Value, value js.Value) {
+	descriptor := globalConstructor("Object").New()
+	descriptor.Set("value", value)
+	descriptor.Set("configurable", true)
+	descriptor.Set("writable", true)
+	globalConstructor("Object").Call("defineProperty", x, symbol, descriptor)
+}
@@ -0,0 +1,98 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWellKnownSymbols(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol Symbol
+		expr   string
+	}{
+		{"iterator", SymbolIterator(), "Symbol.iterator"},
+		{"asyncIterator", SymbolAsyncIterator(), "Symbol.asyncIterator"},
+		{"toPrimitive", SymbolToPrimitive(), "Symbol.toPrimitive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.symbol.JSValue().Equal(evalJS(tt.expr)) {
+				t.Errorf("%s() is not %s", tt.name, tt.expr)
+			}
+			if want := tt.expr; tt.symbol.Description() != want {
+				t.Errorf("Description() = %q, want %q", tt.symbol.Description(), want)
+			}
+		})
+	}
+}
+
+func TestSymbolFor(t *testing.T) {
+	s := SymbolFor("app.key")
+	if !s.Equal(SymbolFor("app.key")) {
+		t.Error("SymbolFor returned different symbols for the same key")
+	}
+	if !s.JSValue().Equal(evalJS(`Symbol.for("app.key")`)) {
+		t.Error("SymbolFor did not return the symbol registered in JS")
+	}
+	if s.Equal(NewSymbol("app.key")) {
+		t.Error("NewSymbol returned the registered symbol")
+	}
+}
+
+func TestSymbolDescription(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol Symbol
+		want   string
+	}{
+		{"description", NewSymbol("id"), "id"},
+		{"empty", NewSymbol(""), ""},
+		{"none", Symbol{evalJS("Symbol()")}, ""},
+		{"registered", SymbolFor("key"), "key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.symbol.Description(); got != tt.want {
+				t.Errorf("Description() = %q, want %q", got, tt.want)
+			}
+			if got, want := tt.symbol.String(), "Symbol("+tt.want+")"; got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeSymbol(t *testing.T) {
+	var s Symbol
+	if err := FromJSValue(evalJS(`Symbol.for("decoded")`), &s); err != nil {
+		t.Fatalf("FromJSValue returned error: %v", err)
+	}
+	if !s.Equal(SymbolFor("decoded")) {
+		t.Errorf("FromJSValue decoded %v, want Symbol(decoded)", s)
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr interface{}
+	}{
+		{"string", `"decoded"`, new(InvalidTypeError)},
+		{"object", `({})`, new(InvalidTypeError)},
+		{"BigInt", `1n`, new(InvalidBigIntError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Symbol
+			if err := s.FromJSValue(evalJS(tt.expr)); !errors.As(err, tt.wantErr) {
+				t.Errorf("Symbol.FromJSValue(%s) returned %v, want a %T", tt.expr, err, tt.wantErr)
+			}
+			if err := FromJSValue(evalJS(tt.expr), &s); !errors.As(err, tt.wantErr) {
+				t.Errorf("FromJSValue(%s) returned %v, want a %T", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
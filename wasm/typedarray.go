@@ -0,0 +1,150 @@
+package wasm
+
+import (
+	"reflect"
+	"syscall/js"
+	"unsafe"
+)
+
+// typedArrays lists the JS TypedArray constructors that share their memory layout with a Go numeric type.
+// 64-bit integers are left out as their typed arrays hold BigInts rather than numbers.
+var typedArrays = []struct {
+	constructor string
+	elem        reflect.Type
+}{
+	{"Int8Array", reflect.TypeOf(int8(0))},
+	{"Int16Array", reflect.TypeOf(int16(0))},
+	{"Int32Array", reflect.TypeOf(int32(0))},
+	{"Uint8Array", reflect.TypeOf(uint8(0))},
+	{"Uint16Array", reflect.TypeOf(uint16(0))},
+	{"Uint32Array", reflect.TypeOf(uint32(0))},
+	{"Float32Array", reflect.TypeOf(float32(0))},
+	{"Float64Array", reflect.TypeOf(float64(0))},
+}
+
+// typedArrayName returns the name of the TypedArray constructor matching the element type of the provided slice or
+// array type.
+// Element types that encode themselves, such as implementations of Wrapper, are never converted to a TypedArray.
+func typedArrayName(t reflect.Type) (string, bool) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return "", false
+	}
+
+	elem := t.Elem()
+	if hasCustomEncoding(elem) {
+		return "", false
+	}
+
+	for _, v := range typedArrays {
+		if v.elem.Kind() == elem.Kind() {
+			return v.constructor, true
+		}
+	}
+	return "", false
+}
+
+// toJSTypedArray converts the provided slice or array of numbers to a TypedArray by copying its memory in bulk.
+func toJSTypedArray(x reflect.Value, name string) js.Value {
+	if x.Kind() == reflect.Array {
+		slice := reflect.MakeSlice(reflect.SliceOf(x.Type().Elem()), x.Len(), x.Len())
+		reflect.Copy(slice, x)
+		x = slice
+	}
+
+	bytes := bytesToJS(sliceBytes(x))
+	if name == "Uint8Array" {
+		return bytes
+	}
+
+	return globalConstructor(name).New(bytes.Get("buffer"), 0, x.Len())
+}
+
+// bytesToJS copies the provided bytes into a new Uint8Array.
+func bytesToJS(b []byte) js.Value {
+	array := globalConstructor("Uint8Array").New(len(b))
+	js.CopyBytesToJS(array, b)
+	return array
+}
+
+// decodeTypedArray decodes a JS TypedArray or ArrayBuffer into the provided reflect.Value.
+// If the TypedArray matches the Go element type, its memory is copied in bulk.
+// Otherwise, it is decoded element by element like a regular array.
+func decodeTypedArray(d *decodeState, x js.Value, v reflect.Value) error {
+	x = typedArrayView(x)
+
+	name, ok := typedArrayName(v.Type())
+	if !ok {
+		return decodeArray(d, x, v)
+	}
+	if !x.InstanceOf(globalConstructor(name)) {
+		return decodeArray(d, x, v)
+	}
+
+	jsLen := x.Length()
+	if err := d.checkLength(jsLen); err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if jsLen != v.Len() {
+			return InvalidArrayError{v.Len(), jsLen}
+		}
+		v = v.Slice(0, jsLen)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), jsLen, jsLen))
+	}
+
+	bytes := globalConstructor("Uint8Array").New(x.Get("buffer"), x.Get("byteOffset"), x.Get("byteLength"))
+	js.CopyBytesToGo(sliceBytes(v), bytes)
+	return nil
+}
+
+// createTypedArray creates a Go slice with the same element type as the provided TypedArray or ArrayBuffer.
+// TypedArrays without a Go equivalent are created as a slice of interface.
+func createTypedArray(d *decodeState, x js.Value) (interface{}, error) {
+	x = typedArrayView(x)
+
+	for _, v := range typedArrays {
+		if !x.InstanceOf(globalConstructor(v.constructor)) {
+			continue
+		}
+
+		result := reflect.New(reflect.SliceOf(v.elem))
+		err := decodeTypedArray(d, x, result.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return result.Elem().Interface(), nil
+	}
+
+	return createArray(d, x)
+}
+
+// typedArrayView returns a Uint8Array over the provided value if it is an ArrayBuffer.
+// Other values are returned as is.
+func typedArrayView(x js.Value) js.Value {
+	if !x.InstanceOf(globalConstructor("ArrayBuffer")) {
+		return x
+	}
+	return globalConstructor("Uint8Array").New(x)
+}
+
+// isTypedArray checks if the provided js.Value is a TypedArray or an ArrayBuffer.
+// A DataView is not considered to be a TypedArray.
+func isTypedArray(x js.Value) bool {
+	arrayBuffer := globalConstructor("ArrayBuffer")
+	if x.InstanceOf(arrayBuffer) {
+		return true
+	}
+	return arrayBuffer.Call("isView", x).Bool() && !x.InstanceOf(globalConstructor("DataView"))
+}
+
+// sliceBytes returns the memory backing the provided slice of numbers as a byte slice without copying it.
+func sliceBytes(x reflect.Value) []byte {
+	size := x.Len() * int(x.Type().Elem().Size())
+	if size == 0 {
+		return nil
+	}
+	return (*[1 << 40]byte)(unsafe.Pointer(x.Pointer()))[:size:size]
+}
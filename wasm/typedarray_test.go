@@ -0,0 +1,94 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypedArrayRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          interface{}
+		constructor string
+	}{
+		{"bytes", []byte{0, 1, 255}, "Uint8Array"},
+		{"int8", []int8{-128, 0, 127}, "Int8Array"},
+		{"uint16", []uint16{0, 65535}, "Uint16Array"},
+		{"int32", []int32{-1 << 31, 1<<31 - 1}, "Int32Array"},
+		{"float32", []float32{-1.5, 0, 3.25}, "Float32Array"},
+		{"float64", []float64{-1.5, 1e300}, "Float64Array"},
+		{"array", [3]int16{-32768, 0, 32767}, "Int16Array"},
+		{"empty", []uint32{}, "Uint32Array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := ToJSValue(tt.in)
+			if !x.InstanceOf(globalConstructor(tt.constructor)) {
+				t.Fatalf("ToJSValue(%v) = %v, want a %s", tt.in, x, tt.constructor)
+			}
+			if x.Length() != reflect.ValueOf(tt.in).Len() {
+				t.Fatalf("ToJSValue(%v) has length %d", tt.in, x.Length())
+			}
+
+			out := reflect.New(reflect.TypeOf(tt.in))
+			if err := FromJSValue(x, out.Interface()); err != nil {
+				t.Fatalf("FromJSValue returned error: %v", err)
+			}
+			if !reflect.DeepEqual(out.Elem().Interface(), tt.in) {
+				t.Errorf("round trip of %v = %v", tt.in, out.Elem().Interface())
+			}
+		})
+	}
+}
+
+func TestDecodeTypedArray(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		into interface{}
+		want interface{}
+	}{
+		{"ArrayBuffer", `new Uint8Array([1, 2, 3]).buffer`, new([]byte), []byte{1, 2, 3}},
+		{"ArrayBuffer into interface", `new Uint8Array([1, 2]).buffer`, new(interface{}), []uint8{1, 2}},
+		{"TypedArray into interface", `new Float32Array([1.5])`, new(interface{}), []float32{1.5}},
+		{"subarray", `new Int16Array([1, 2, 3, 4]).subarray(1, 3)`, new([]int16), []int16{2, 3}},
+		{"other element type", `new Int16Array([1, -2])`, new([]float32), []float32{1, -2}},
+		{"fractions into integers", `new Float64Array([1.5, -2.5])`, new([]int8), []int8{1, -2}},
+		{"array of numbers", `[1, 2]`, new([]uint16), []uint16{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := FromJSValue(evalJS(tt.expr), tt.into); err != nil {
+				t.Fatalf("FromJSValue returned error: %v", err)
+			}
+			if got := reflect.ValueOf(tt.into).Elem().Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromJSValue(%s) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTypedArrayLength(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"shorter", `new Int16Array([1, 2])`},
+		{"longer", `new Int16Array([1, 2, 3, 4])`},
+		{"other element type", `new Int32Array([1, 2])`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out [3]int16
+			err := FromJSValue(evalJS(tt.expr), &out)
+			var arrayErr InvalidArrayError
+			if !errors.As(err, &arrayErr) || arrayErr.Expected != 3 {
+				t.Errorf("FromJSValue returned %v, want an InvalidArrayError", err)
+			}
+		})
+	}
+}
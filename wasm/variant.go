@@ -0,0 +1,167 @@
+package wasm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"syscall/js"
+)
+
+// UnknownVariantError is an error where the discriminator property of a JS object decoded into a registered interface
+// does not name any of its variants.
+type UnknownVariantError struct {
+	Property string
+	Value    string
+	GoType   reflect.Type
+}
+
+// Error implements error.
+func (e UnknownVariantError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: unknown %s %q for %s", e.Property, e.Value, e.GoType)
+}
+
+// MissingVariantError is an error where a JS object decoded into a registered interface lacks the discriminator
+// property, or sets it to null.
+type MissingVariantError struct {
+	Property string
+	GoType   reflect.Type
+}
+
+// Error implements error.
+func (e MissingVariantError) Error() string {
+	return fmt.Sprintf("invalid unmarshalling: missing %s property to pick the variant of %s", e.Property, e.GoType)
+}
+
+// variants holds the concrete types registered for an interface type, keyed by the value of the discriminator.
+type variants struct {
+	property string
+	types    map[string]reflect.Type
+	values   map[reflect.Type]string
+}
+
+var (
+	variantsMu sync.RWMutex
+
+	// interfaceVariants holds the variants registered for specific interface types.
+	interfaceVariants = map[reflect.Type]*variants{}
+)
+
+// RegisterVariants registers the concrete types of the interface pointed to by iface, such as (*Shape)(nil), so that
+// values of the interface can be converted from and to JS objects.
+//
+// FromJSValue reads the string property of the JS object named by property, and decodes the object into a new value
+// of the type registered for it in types, such as Circle{} for "circle", returning an UnknownVariantError for other
+// values and a MissingVariantError if the property is missing. Registering a pointer, such as &Circle{}, decodes into
+// a pointer instead. ToJSValue sets the property on the JS object of a registered type in turn.
+//
+// It panics if iface does not point to an interface or if a type does not implement it.
+// It should be called before any value of the interface is converted, such as in an init function.
+func RegisterVariants(iface interface{}, property string, types map[string]interface{}) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic("cannot register variants as the provided value is not a pointer to an interface")
+	}
+	t = t.Elem()
+
+	vs := &variants{
+		property: property,
+		types:    map[string]reflect.Type{},
+		values:   map[reflect.Type]string{},
+	}
+	for value, x := range types {
+		vt := reflect.TypeOf(x)
+		if vt == nil || !vt.Implements(t) {
+			panic(fmt.Sprintf("cannot register %v as a variant of %s as it does not implement it", vt, t))
+		}
+		vs.types[value] = vt
+		vs.values[vt] = value
+	}
+
+	variantsMu.Lock()
+	interfaceVariants[t] = vs
+	variantsMu.Unlock()
+
+	resetTypeCaches()
+}
+
+// typeVariants returns the variants registered for the provided interface type.
+func typeVariants(t reflect.Type) (*variants, bool) {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+
+	vs, ok := interfaceVariants[t]
+	return vs, ok
+}
+
+// variantProperties returns the discriminator properties that ToJSValue sets on the JS object of the provided struct
+// type, or of a pointer to it, when it is registered as a variant.
+func variantProperties(t reflect.Type) []string {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+
+	var properties []string
+	for _, vs := range interfaceVariants {
+		_, isValue := vs.values[t]
+		_, isPtr := vs.values[reflect.PtrTo(t)]
+		if isValue || isPtr {
+			properties = append(properties, vs.property)
+		}
+	}
+	return properties
+}
+
+// newVariantEncoder returns an encoderFunc that converts a value of an interface with registered variants, setting
+// the discriminator property on the JS object of its concrete type.
+func newVariantEncoder(vs *variants) encoderFunc {
+	return func(e *encodeState, v reflect.Value) js.Value {
+		if v.IsNil() {
+			return js.Null()
+		}
+
+		obj := e.encode(v.Elem().Interface())
+		if value, ok := vs.values[v.Elem().Type()]; ok && obj.Type() == js.TypeObject {
+			obj.Set(vs.property, value)
+		}
+		return obj
+	}
+}
+
+// newVariantDecoder returns a decoderFunc that decodes a JS object into a new value of the variant named by its
+// discriminator property.
+func newVariantDecoder(t reflect.Type, vs *variants) decoderFunc {
+	return func(d *decodeState, x js.Value, v reflect.Value) error {
+		if !isObject(x) {
+			return InvalidTypeError{x.Type(), t}
+		}
+
+		property := x.Get(vs.property)
+		if property.IsUndefined() || property.IsNull() {
+			return MissingVariantError{
+				Property: vs.property,
+				GoType:   t,
+			}
+		}
+
+		var value string
+		discriminator := reflect.ValueOf(&value).Elem()
+		if err := decodeString(d, property, discriminator); err != nil {
+			return withProperty(err, vs.property, property, discriminator.Type())
+		}
+
+		vt, ok := vs.types[value]
+		if !ok {
+			return UnknownVariantError{
+				Property: vs.property,
+				Value:    value,
+				GoType:   t,
+			}
+		}
+
+		variant := reflect.New(vt).Elem()
+		if err := typeDecoder(vt)(d, x, variant); err != nil {
+			return err
+		}
+		v.Set(variant)
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type variantShape interface {
+	Area() float64
+}
+
+type variantCircle struct {
+	Radius float64 `wasm:"radius"`
+}
+
+func (c variantCircle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type variantSquare struct {
+	Side float64 `wasm:"side"`
+}
+
+func (s *variantSquare) Area() float64 { return s.Side * s.Side }
+
+func init() {
+	RegisterVariants((*variantShape)(nil), "type", map[string]interface{}{
+		"circle": variantCircle{},
+		"square": &variantSquare{},
+	})
+}
+
+func TestDecodeVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    variantShape
+		wantErr interface{}
+	}{
+		{"value", `{type: "circle", radius: 2}`, variantCircle{Radius: 2}, nil},
+		{"pointer", `{type: "square", side: 3}`, &variantSquare{Side: 3}, nil},
+		{"unknown", `{type: "triangle"}`, nil, new(UnknownVariantError)},
+		{"missing", `{radius: 2}`, nil, new(MissingVariantError)},
+		{"null", `{type: null}`, nil, new(MissingVariantError)},
+		{"not a string", `{type: 1}`, nil, new(InvalidTypeError)},
+		{"not an object", `"circle"`, nil, new(InvalidTypeError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got variantShape
+			err := FromJSValue(evalJS(tt.expr), &got)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Errorf("FromJSValue(%s) returned %v, want a %T", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromJSValue(%s) returned error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromJSValue(%s) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape variantShape
+		want  string
+	}{
+		{"value", variantCircle{Radius: 2}, "circle"},
+		{"pointer", &variantSquare{Side: 3}, "square"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shapes := []variantShape{tt.shape}
+			x := ToJSValue(shapes).Index(0)
+			if got := x.Get("type"); got.String() != tt.want {
+				t.Errorf("ToJSValue set type to %v, want %q", got, tt.want)
+			}
+
+			var back []variantShape
+			if err := FromJSValue(ToJSValue(shapes), &back); err != nil {
+				t.Fatalf("FromJSValue returned error: %v", err)
+			}
+			if !reflect.DeepEqual(back, shapes) {
+				t.Errorf("round trip = %#v, want %#v", back, shapes)
+			}
+		})
+	}
+}
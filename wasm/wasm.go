@@ -1,6 +1,9 @@
 package wasm
 
-import "syscall/js"
+import (
+	"sync"
+	"syscall/js"
+)
 
 // Magic values to communicate with the JS library.
 const (
@@ -12,6 +15,9 @@ const (
 var (
 	bridge      Object
 	funcWrapper js.Value
+
+	// constructors caches the global constructors by name, as they are needed for nearly every conversion.
+	constructors sync.Map // map[string]js.Value
 )
 
 func init() {
@@ -38,6 +44,34 @@ func Ready() {
 }
 
 // Expose exposes a copy of the provided value in JS.
-func Expose(property string, x interface{}) {
-	bridge.Set(property, x)
+// Use ExposeLive to expose a struct whose fields JS can read and write.
+// It returns a CycleError if the value refers back to itself, in which case nothing is exposed.
+func Expose(property string, x interface{}) error {
+	return defaultEncoder.Expose(property, x)
+}
+
+// Expose exposes a copy of the provided value in JS like the package-level Expose, converting it with the options of
+// the Encoder. Exposed functions decode their arguments with its Arguments options, such as StrictNumbers.
+func (enc *Encoder) Expose(property string, x interface{}) error {
+	v, err := enc.Encode(x)
+	if err != nil {
+		return err
+	}
+	bridge.value.Set(property, v)
+	return nil
+}
+
+// globalConstructor returns the global constructor with the provided name, looking it up only once.
+// It panics if the constructor is not found.
+func globalConstructor(name string) js.Value {
+	if c, ok := constructors.Load(name); ok {
+		return c.(js.Value)
+	}
+
+	c, err := Global().Expect(js.TypeFunction, name)
+	if err != nil {
+		panic(name + " constructor not found")
+	}
+	constructors.Store(name, c)
+	return c
 }
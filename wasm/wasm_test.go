@@ -0,0 +1,25 @@
+package wasm
+
+import (
+	"syscall/js"
+)
+
+// bridgeDefined defines the JS bridge that the JS library sets up before running the WASM, as init expects it.
+// Package-level variables are initialized before init functions run, including the ones of test files.
+var bridgeDefined = defineBridge()
+
+// defineBridge defines the bridge with the same function wrapper as the JS library.
+func defineBridge() bool {
+	bridge := js.Global().Get("Object").New()
+	bridge.Set(funcWrapperName, js.Global().Get("Function").New("goFunc", `
+		return (...args) => {
+			const result = goFunc.apply(undefined, args);
+			if (result.error instanceof Error) {
+				throw result.error;
+			}
+			return result.result;
+		};
+	`))
+	js.Global().Set(globalIdent, bridge)
+	return true
+}